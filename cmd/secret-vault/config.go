@@ -3,8 +3,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -17,29 +19,89 @@ type Config struct {
 	Addr string
 	// enables setting the type of authentication method
 	AuthMethod string
+	// enables setting the mount path of the authentication method
+	AuthMount string
+	// enables setting the CA certificate for verifying the instance
+	CACert string
+	// enables setting the directory of CA certificates for verifying the instance
+	CAPath string
+	// enables setting the client certificate for communicating with the instance
+	ClientCert string
+	// enables setting the client key for communicating with the instance
+	ClientKey string
+	// enables skipping verification of the instance certificate
+	Insecure bool
+	// enables setting the JWT for authentication
+	JWT string
+	// enables setting the file containing the JWT for authentication
+	JWTFile string
+	// enables setting the file containing the service account token for authentication
+	KubernetesTokenPath string
+	// enables setting the Vault Enterprise namespace
+	Namespace string
 	// enables setting the password for authentication
 	Password string
+	// enables setting the role for authentication
+	Role string
+	// enables setting the role ID for authentication
+	RoleID string
+	// enables setting the secret ID for authentication
+	SecretID string
+	// enables setting the timeout for each attempt of a request
+	RequestTimeout time.Duration
+	// enables setting the maximum number of attempts of a request
+	RetryAttempts int
+	// enables setting the initial time to wait between attempts of a request
+	RetryBackoff time.Duration
+	// enables setting the fraction of the time to wait between attempts to randomly add
+	RetryJitter float64
+	// enables setting the overall deadline for logging in and reading
+	Timeout time.Duration
+	// enables setting the server name for verifying the instance
+	TLSServerName string
 	// enables setting the token for for authentication
 	Token string
 	// enables setting the username for authentication
 	Username string
+	// enables setting the wrapped secret ID for authentication
+	WrappedSecretID string
 }
 
 // New creates an Vault client for reading secrets.
-func (c *Config) New() (*vault.Client, error) {
+func (c *Config) New(ctx context.Context) (*vault.Client, error) {
 	logrus.Trace("creating new Vault client from plugin configuration")
 
 	// add the Vault specific config info to setup a client
 	s := &vault.Setup{
-		Addr:       c.Addr,
-		AuthMethod: c.AuthMethod,
-		Password:   c.Password,
-		Token:      c.Token,
-		Username:   c.Username,
+		Addr:                c.Addr,
+		AuthMethod:          c.AuthMethod,
+		AuthMount:           c.AuthMount,
+		CACert:              c.CACert,
+		CAPath:              c.CAPath,
+		ClientCert:          c.ClientCert,
+		ClientKey:           c.ClientKey,
+		Insecure:            c.Insecure,
+		JWT:                 c.JWT,
+		JWTFile:             c.JWTFile,
+		KubernetesTokenPath: c.KubernetesTokenPath,
+		Namespace:           c.Namespace,
+		Password:            c.Password,
+		Role:                c.Role,
+		RoleID:              c.RoleID,
+		SecretID:            c.SecretID,
+		RequestTimeout:      c.RequestTimeout,
+		RetryAttempts:       c.RetryAttempts,
+		RetryBackoff:        c.RetryBackoff,
+		RetryJitter:         c.RetryJitter,
+		Timeout:             c.Timeout,
+		TLSServerName:       c.TLSServerName,
+		Token:               c.Token,
+		Username:            c.Username,
+		WrappedSecretID:     c.WrappedSecretID,
 	}
 
 	// setup connection with Vault
-	client, err := vault.New(s)
+	client, err := vault.New(ctx, s)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +127,20 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("no auth method provided")
 	}
 
+	// verify client certificate and key are provided together
+	if (len(c.ClientCert) == 0) != (len(c.ClientKey) == 0) {
+		return fmt.Errorf("invalid tls configuration passed. Must set both client_cert and client_key")
+	}
+
+	// verify the retry policy is valid
+	if c.RetryAttempts < 0 || c.RetryBackoff < 0 || c.RequestTimeout < 0 || c.Timeout < 0 {
+		return fmt.Errorf("invalid retry configuration passed. Must set retry_attempts, retry_backoff, request_timeout and timeout to positive values")
+	}
+
+	if c.RetryJitter < 0 || c.RetryJitter > 1 {
+		return fmt.Errorf("invalid retry configuration passed. Must set retry_jitter between 0 and 1")
+	}
+
 	// verify provided authentication is valid for authentication
 	switch c.AuthMethod {
 	case vault.TokenAuthMethod:
@@ -76,6 +152,38 @@ func (c *Config) Validate() error {
 		if len(c.Password) == 0 && len(c.Username) == 0 {
 			return fmt.Errorf("invalid authentication passed. Must set username and password for %s auth method", vault.LDAPAuthMethod)
 		}
+
+	case vault.JWTAuthMethod:
+		if len(c.Role) == 0 {
+			return fmt.Errorf("invalid authentication passed. Must set role for %s auth method", vault.JWTAuthMethod)
+		}
+
+		if len(c.JWT) == 0 && len(c.JWTFile) == 0 {
+			return fmt.Errorf("invalid authentication passed. Must set jwt or jwt_file for %s auth method", vault.JWTAuthMethod)
+		}
+
+	case vault.KubernetesAuthMethod:
+		if len(c.Role) == 0 {
+			return fmt.Errorf("invalid authentication passed. Must set role for %s auth method", vault.KubernetesAuthMethod)
+		}
+
+	case vault.CertAuthMethod:
+		if len(c.ClientCert) == 0 {
+			return fmt.Errorf("invalid authentication passed. Must set client_cert and client_key for %s auth method", vault.CertAuthMethod)
+		}
+
+	case vault.AppRoleAuthMethod:
+		if len(c.RoleID) == 0 {
+			return fmt.Errorf("invalid authentication passed. Must set role_id for %s auth method", vault.AppRoleAuthMethod)
+		}
+
+		if len(c.SecretID) == 0 && len(c.WrappedSecretID) == 0 {
+			return fmt.Errorf("invalid authentication passed. Must set secret_id or wrapped_secret_id for %s auth method", vault.AppRoleAuthMethod)
+		}
+
+		if len(c.SecretID) > 0 && len(c.WrappedSecretID) > 0 {
+			return fmt.Errorf("invalid authentication passed. Must set only one of secret_id or wrapped_secret_id for %s auth method", vault.AppRoleAuthMethod)
+		}
 	}
 
 	return nil
@@ -36,7 +36,7 @@ func TestVault_Config_New(t *testing.T) {
 
 	// run test
 	for _, test := range tests {
-		got, err := test.config.New()
+		got, err := test.config.New(t.Context())
 		if err != nil {
 			t.Errorf("New returned err: %v", err)
 		}
@@ -70,6 +70,51 @@ func TestVault_Config_Validate(t *testing.T) {
 			},
 			err: nil,
 		},
+		{ // valid config with jwt auth method
+			config: &Config{
+				Addr:       "https://myvault.com/",
+				AuthMethod: vault.JWTAuthMethod,
+				JWTFile:    "/vela/id_token",
+				Role:       "myrole",
+			},
+			err: nil,
+		},
+		{ // valid config with kubernetes auth method
+			config: &Config{
+				Addr:       "https://myvault.com/",
+				AuthMethod: vault.KubernetesAuthMethod,
+				Role:       "myrole",
+			},
+			err: nil,
+		},
+		{ // valid config with cert auth method
+			config: &Config{
+				Addr:       "https://myvault.com/",
+				AuthMethod: vault.CertAuthMethod,
+				CACert:     "/vela/ca.pem",
+				ClientCert: "/vela/client.pem",
+				ClientKey:  "/vela/client-key.pem",
+			},
+			err: nil,
+		},
+		{ // valid config with approle auth method
+			config: &Config{
+				Addr:       "https://myvault.com/",
+				AuthMethod: vault.AppRoleAuthMethod,
+				RoleID:     "myroleid",
+				SecretID:   "superSecretID",
+			},
+			err: nil,
+		},
+		{ // valid config with approle auth method and wrapped secret id
+			config: &Config{
+				Addr:            "https://myvault.com/",
+				AuthMethod:      vault.AppRoleAuthMethod,
+				RoleID:          "myroleid",
+				WrappedSecretID: "superSecretWrappingToken",
+			},
+			err: nil,
+		},
 	}
 
 	// run test
@@ -80,3 +125,70 @@ func TestVault_Config_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestVault_Config_Validate_Failure(t *testing.T) {
+	// setup types
+	tests := []*Config{
+		{ // jwt auth method without role
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.JWTAuthMethod,
+			JWT:        "header.payload.signature",
+		},
+		{ // jwt auth method without jwt
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.JWTAuthMethod,
+			Role:       "myrole",
+		},
+		{ // kubernetes auth method without role
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.KubernetesAuthMethod,
+		},
+		{ // cert auth method without client certificate
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.CertAuthMethod,
+		},
+		{ // client certificate without client key
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.TokenAuthMethod,
+			Token:      "superSecretAPIKey",
+			ClientCert: "/vela/client.pem",
+		},
+		{ // negative retry attempts
+			Addr:          "https://myvault.com/",
+			AuthMethod:    vault.TokenAuthMethod,
+			Token:         "superSecretAPIKey",
+			RetryAttempts: -1,
+		},
+		{ // retry jitter above 1
+			Addr:        "https://myvault.com/",
+			AuthMethod:  vault.TokenAuthMethod,
+			Token:       "superSecretAPIKey",
+			RetryJitter: 1.5,
+		},
+		{ // approle auth method without role id
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.AppRoleAuthMethod,
+			SecretID:   "superSecretID",
+		},
+		{ // approle auth method without secret id
+			Addr:       "https://myvault.com/",
+			AuthMethod: vault.AppRoleAuthMethod,
+			RoleID:     "myroleid",
+		},
+		{ // approle auth method with secret id and wrapped secret id
+			Addr:            "https://myvault.com/",
+			AuthMethod:      vault.AppRoleAuthMethod,
+			RoleID:          "myroleid",
+			SecretID:        "superSecretID",
+			WrappedSecretID: "superSecretWrappingToken",
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		err := test.Validate()
+		if err == nil {
+			t.Errorf("Validate should have returned err for %+v", test)
+		}
+	}
+}
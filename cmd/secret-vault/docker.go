@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/server/compiler/types/raw"
+)
+
+const (
+	// DockerAuthPath defines the default path under the secrets
+	// volume the docker config.json file is written to.
+	DockerAuthPath = "docker/config.json"
+
+	// DockerAuthUsername defines the default key in the secret
+	// containing the username for the registries.
+	DockerAuthUsername = "username"
+
+	// DockerAuthPassword defines the default key in the secret
+	// containing the password for the registries.
+	DockerAuthPassword = "password"
+)
+
+// ErrInvalidDockerAuth defines the error type when an
+// invalid docker_auth was provided for a Vault read.
+var ErrInvalidDockerAuth = errors.New("invalid `docker_auth` provided")
+
+// DockerAuth represents how to build a docker config.json
+// file with registry credentials from the secret.
+type DockerAuth struct {
+	// are the registry hosts the credentials are used for
+	Registries raw.StringSlice `json:"registries"`
+	// is the key in the secret containing the username
+	Username string `json:"username"`
+	// is the key in the secret containing the password
+	Password string `json:"password"`
+	// is the path to write the config.json file to in Vela
+	Path string `json:"path"`
+}
+
+// dockerAuthEntry represents the credentials for
+// a registry within a docker config.json file.
+type dockerAuthEntry struct {
+	Auth string `json:"auth"`
+}
+
+// setDefaults sets the default keys and path for any
+// that were not provided for the docker auth.
+func (d *DockerAuth) setDefaults() {
+	if len(d.Username) == 0 {
+		d.Username = DockerAuthUsername
+	}
+
+	if len(d.Password) == 0 {
+		d.Password = DockerAuthPassword
+	}
+
+	if len(d.Path) == 0 {
+		d.Path = DockerAuthPath
+	}
+}
+
+// Validate verifies the docker auth is properly configured.
+func (d *DockerAuth) Validate() error {
+	// verify at least one registry was provided
+	if len(d.Registries) == 0 {
+		return fmt.Errorf("%w: no `registries` provided", ErrInvalidDockerAuth)
+	}
+
+	for _, registry := range d.Registries {
+		if len(registry) == 0 {
+			return fmt.Errorf("%w: empty registry provided", ErrInvalidDockerAuth)
+		}
+	}
+
+	return nil
+}
+
+// execDockerAuth writes the username and password from the `source`
+// as credentials for each registry to the docker config.json file,
+// merging with the file if it already exists in the secrets volume.
+func (r *Read) execDockerAuth(a *afero.Afero, item *Item, secret *api.Secret) error {
+	// copy the docker auth to avoid modifying the item
+	d := *item.DockerAuth
+	d.setDefaults()
+
+	username, err := dockerAuthValue(secret, d.Username)
+	if err != nil {
+		return fmt.Errorf("%w for vault secret at %s: %w", ErrInvalidDockerAuth, item.Source, err)
+	}
+
+	password, err := dockerAuthValue(secret, d.Password)
+	if err != nil {
+		return fmt.Errorf("%w for vault secret at %s: %w", ErrInvalidDockerAuth, item.Source, err)
+	}
+
+	cfg, err := r.readDockerConfig(a, d.Path)
+	if err != nil {
+		return err
+	}
+
+	// preserve any existing credentials in their original form
+	auths := make(map[string]json.RawMessage)
+
+	if existing, ok := cfg["auths"]; ok {
+		err = json.Unmarshal(existing, &auths)
+		if err != nil {
+			return fmt.Errorf("unable to parse auths in docker config %s: %w", d.Path, err)
+		}
+	}
+
+	entry, err := json.Marshal(dockerAuthEntry{
+		Auth: base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to render credentials for docker config %s: %w", d.Path, err)
+	}
+
+	for _, registry := range d.Registries {
+		logrus.Tracef("adding credentials for registry %s to docker config %s", registry, d.Path)
+
+		auths[registry] = entry
+	}
+
+	// maps are serialized with sorted keys
+	cfg["auths"], err = json.Marshal(auths)
+	if err != nil {
+		return fmt.Errorf("unable to render auths for docker config %s: %w", d.Path, err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to render docker config %s: %w", d.Path, err)
+	}
+
+	return r.writeSecretFile(a, d.Path, append(data, '\n'), item.FileOptions)
+}
+
+// dockerAuthValue returns the value of the key in the
+// secret as a string for use in registry credentials.
+func dockerAuthValue(secret *api.Secret, key string) (string, error) {
+	data, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found", key)
+	}
+
+	return formatValue(data, FormatRaw)
+}
+
+// readDockerConfig reads the existing docker config.json
+// file from the secrets volume, preserving any fields
+// other than the registry credentials.
+func (r *Read) readDockerConfig(a *afero.Afero, pth string) (map[string]json.RawMessage, error) {
+	cfg := make(map[string]json.RawMessage)
+
+	file, err := confinePath(a.Fs, r.root(), pth)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := a.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+
+		return nil, fmt.Errorf("unable to read docker config %s: %w", pth, err)
+	}
+
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse docker config %s: %w", pth, err)
+	}
+
+	return cfg, nil
+}
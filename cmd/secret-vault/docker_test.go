@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestVault_Read_Exec_DockerAuth(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/artifactory":
+			_, _ = w.Write([]byte(`{"data":{"username":"octocat","password":"superSecretPassword"}}`))
+		case "/v1/secret/dockerhub":
+			_, _ = w.Write([]byte(`{"data":{"user":"vela","token":"superSecretToken"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// existing docker config with other settings
+	err := a.WriteFile("/vela/secrets/docker/config.json", []byte(`{"auths":{"ghcr.io":{"auth":"Z2hjcg=="}},"credsStore":"desktop"}`), 0600)
+	if err != nil {
+		t.Fatalf("unable to write docker config: %v", err)
+	}
+
+	r := &Read{
+		Items: []*Item{
+			{
+				Source: "secret/artifactory",
+				DockerAuth: &DockerAuth{
+					Registries: []string{"docker.company.com", "mirror.company.com"},
+				},
+			},
+			{
+				Source: "secret/dockerhub",
+				DockerAuth: &DockerAuth{
+					Registries: []string{"https://index.docker.io/v1/"},
+					Username:   "user",
+					Password:   "token",
+				},
+			},
+		},
+	}
+
+	want := `{
+  "auths": {
+    "docker.company.com": {
+      "auth": "b2N0b2NhdDpzdXBlclNlY3JldFBhc3N3b3Jk"
+    },
+    "ghcr.io": {
+      "auth": "Z2hjcg=="
+    },
+    "https://index.docker.io/v1/": {
+      "auth": "dmVsYTpzdXBlclNlY3JldFRva2Vu"
+    },
+    "mirror.company.com": {
+      "auth": "b2N0b2NhdDpzdXBlclNlY3JldFBhc3N3b3Jk"
+    }
+  },
+  "credsStore": "desktop"
+}
+`
+
+	err = r.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	// run test
+	err = r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	got, err := a.ReadFile("/vela/secrets/docker/config.json")
+	if err != nil {
+		t.Fatalf("unable to read docker config: %v", err)
+	}
+
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Exec docker config mismatch (-want +got):\n%s", diff)
+	}
+
+	// missing key in secret
+	r.Items = []*Item{
+		{
+			Source: "secret/dockerhub",
+			DockerAuth: &DockerAuth{
+				Registries: []string{"https://index.docker.io/v1/"},
+			},
+		},
+	}
+
+	err = r.Exec(t.Context(), v)
+	if !errors.Is(err, ErrInvalidDockerAuth) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrInvalidDockerAuth)
+	}
+}
+
+func TestVault_Read_Unmarshal_DockerAuth(t *testing.T) {
+	// setup types
+	r := &Read{
+		RawItems: `[{"source": "secret/artifactory", "docker_auth": {"registries": ["docker.company.com"], "path": "kaniko/config.json"}}]`,
+	}
+
+	want := &DockerAuth{
+		Registries: []string{"docker.company.com"},
+		Path:       "kaniko/config.json",
+	}
+
+	// run test
+	err := r.Unmarshal()
+	if err != nil {
+		t.Fatalf("Unmarshal returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Items[0].DockerAuth); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+
+	err = r.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+}
+
+func TestVault_Read_Validate_DockerAuth(t *testing.T) {
+	// setup types
+	tests := []*DockerAuth{
+		{}, // no registries
+		{Registries: []string{""}},
+		{Registries: []string{"docker.company.com", ""}},
+	}
+
+	// run test
+	for _, test := range tests {
+		r := &Read{
+			Items: []*Item{{Source: "secret/artifactory", DockerAuth: test}},
+		}
+
+		err := r.Validate()
+		if !errors.Is(err, ErrInvalidDockerAuth) {
+			t.Errorf("Validate returned err %v, want %v", err, ErrInvalidDockerAuth)
+		}
+	}
+}
+
+func TestVault_Read_Validate_DockerAuth_Path(t *testing.T) {
+	// setup types
+	r := &Read{
+		Items: []*Item{
+			{
+				Source:     "secret/artifactory",
+				Path:       []string{"artifactory"},
+				DockerAuth: &DockerAuth{Registries: []string{"docker.company.com"}},
+			},
+		},
+	}
+
+	// run test
+	err := r.Validate()
+	if !errors.Is(err, ErrInvalidDockerAuth) {
+		t.Errorf("Validate returned err %v, want %v", err, ErrInvalidDockerAuth)
+	}
+}
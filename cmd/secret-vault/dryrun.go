@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/secret-vault/vault"
+)
+
+const (
+	// planOK defines the status for a check that succeeded.
+	planOK = "ok"
+
+	// planFailed defines the status for a check that failed.
+	planFailed = "failed"
+)
+
+var (
+	// ErrDryRunFailed defines the error type when
+	// any of the checks for a dry run failed.
+	ErrDryRunFailed = errors.New("dry run failed")
+
+	// planOutput is the destination for the table reported
+	// by a dry run which enables us to test.
+	planOutput io.Writer = os.Stdout
+)
+
+// planResult represents the result of checking access
+// to a secret, or a key of a secret, for an item.
+type planResult struct {
+	Item   int
+	Source string
+	Key    string
+	Err    error
+	Detail string
+}
+
+// plan checks access to every secret and key for the items without
+// writing anything to the secrets volume or outputs file, reporting
+// the result of each check in a table.
+//
+// dynamic secrets read with a POST request are only checked with the
+// capabilities of the token to avoid generating credentials.
+func (r *Read) plan(ctx context.Context, v *vault.Client) error {
+	logrus.Info("running dry run, no secrets will be written")
+
+	r.reset()
+
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// send the requests for all items to Vault up front
+	r.prefetch(ctx, v)
+
+	results := []planResult{}
+
+	for i, item := range r.Items {
+		// stop checking items when the plugin is canceled
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		results = append(results, r.planItem(ctx, v, a, i, item)...)
+	}
+
+	// revoke the token created when logging in as it is not recorded
+	if len(v.Accessor) > 0 {
+		err := v.RevokeSelf(ctx)
+		if err != nil {
+			logrus.Warnf("unable to revoke token created for dry run: %v", err)
+		}
+	}
+
+	failed := 0
+
+	w := tabwriter.NewWriter(planOutput, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ITEM\tSOURCE\tKEY\tSTATUS\tDETAIL")
+
+	for _, result := range results {
+		status, detail := planOK, result.Detail
+
+		if result.Err != nil {
+			failed++
+
+			status, detail = planFailed, result.Err.Error()
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", result.Item, result.Source, result.Key, status, detail)
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return fmt.Errorf("unable to write dry run results: %w", err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d checks failed", ErrDryRunFailed, failed, len(results))
+	}
+
+	logrus.Infof("dry run succeeded: %d checks passed", len(results))
+
+	return nil
+}
+
+// planItem checks access to the secrets and keys for the item.
+func (r *Read) planItem(ctx context.Context, v *vault.Client, a *afero.Afero, i int, item *Item) []planResult {
+	switch {
+	case item.isTemplate():
+		return r.planTemplateItem(ctx, v, a, i, item)
+	case item.isGlob():
+		return r.planGlobItem(ctx, v, i, item)
+	case !planRead(ctx, v, item):
+		return []planResult{planCapabilities(ctx, v, i, item)}
+	}
+
+	secret, err := r.planSecret(ctx, v, item)
+	if err != nil {
+		return []planResult{{Item: i, Source: item.Source, Err: err}}
+	}
+
+	results := []planResult{}
+
+	// check each key exists and can be written as configured
+	for _, keyItem := range item.Keys {
+		results = append(results, planResult{
+			Item:   i,
+			Source: item.Source,
+			Key:    keyItem.Name + keyItem.Select,
+			Err:    planKeyItem(secret, keyItem),
+		})
+	}
+
+	if len(item.File) > 0 {
+		_, err := renderSecret(secret.Data, item.Format)
+
+		results = append(results, planResult{Item: i, Source: item.Source, Key: item.File, Err: err, Detail: item.Format})
+	}
+
+	if item.DockerAuth != nil {
+		d := *item.DockerAuth
+		d.setDefaults()
+
+		_, err := dockerAuthValue(secret, d.Username)
+		if err == nil {
+			_, err = dockerAuthValue(secret, d.Password)
+		}
+
+		results = append(results, planResult{Item: i, Source: item.Source, Key: d.Path, Err: err, Detail: "docker_auth"})
+	}
+
+	if len(results) == 0 {
+		results = append(results, planResult{
+			Item:   i,
+			Source: item.Source,
+			Detail: fmt.Sprintf("%d key(s)", len(secret.Data)),
+		})
+	}
+
+	return results
+}
+
+// planKeyItem checks the key exists in the secret
+// and the value can be written as configured.
+func planKeyItem(secret *api.Secret, keyItem KeyItem) error {
+	data, ok := secret.Data[keyItem.Name]
+	if !ok {
+		return fmt.Errorf("key %s not found", keyItem.Name)
+	}
+
+	var err error
+
+	if len(keyItem.Select) > 0 {
+		data, err = selectValue(data, keyItem.Select)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = formatValue(data, keyItem.Format)
+
+	return err
+}
+
+// planTemplateItem checks access to each of the sources
+// for the template and that the template renders.
+func (r *Read) planTemplateItem(ctx context.Context, v *vault.Client, a *afero.Afero, i int, item *Item) []planResult {
+	names := make([]string, 0, len(item.Sources))
+	for name := range item.Sources {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	results := []planResult{}
+	data := make(map[string]interface{}, len(names))
+
+	for _, name := range names {
+		source := &Item{Source: item.Sources[name], Namespace: item.Namespace}
+
+		if !planRead(ctx, v, source) {
+			result := planCapabilities(ctx, v, i, source)
+			result.Key = name
+
+			results = append(results, result)
+
+			continue
+		}
+
+		secret, err := r.planSecret(ctx, v, source)
+		if err == nil {
+			data[name] = secret.Data
+		}
+
+		results = append(results, planResult{Item: i, Source: item.Sources[name], Key: name, Err: err})
+	}
+
+	// only render the template when all sources are available
+	if len(data) == len(names) {
+		tmpl, err := parseTemplate(a, item)
+		if err == nil {
+			err = tmpl.Execute(io.Discard, data)
+		}
+
+		results = append(results, planResult{Item: i, Key: item.File, Err: err, Detail: "template"})
+	}
+
+	return results
+}
+
+// planGlobItem checks access to each of the secrets matching the glob source.
+func (r *Read) planGlobItem(ctx context.Context, v *vault.Client, i int, item *Item) []planResult {
+	list := r.fetcher.list(ctx, v, item)
+	if list.err != nil {
+		return []planResult{{Item: i, Source: item.Source, Err: list.err}}
+	}
+
+	if len(list.paths) == 0 {
+		return []planResult{{Item: i, Source: item.Source, Err: ErrNoSecretsFound}}
+	}
+
+	results := []planResult{}
+
+	for _, source := range list.paths {
+		sourceItem := &Item{Source: source, Namespace: item.Namespace}
+
+		if !planRead(ctx, v, sourceItem) {
+			results = append(results, planCapabilities(ctx, v, i, sourceItem))
+
+			continue
+		}
+
+		secret, err := r.planSecret(ctx, v, sourceItem)
+
+		result := planResult{Item: i, Source: source, Err: err}
+		if err == nil {
+			result.Detail = fmt.Sprintf("%d key(s)", len(secret.Data))
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// planSecret reads the secret for the item, immediately revoking
+// the lease for any dynamic secret as it is not recorded.
+func (r *Read) planSecret(ctx context.Context, v *vault.Client, item *Item) (*api.Secret, error) {
+	result := r.fetcher.secret(ctx, v, item)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	if len(result.secret.LeaseID) > 0 && !result.recorded {
+		result.recorded = true
+
+		client := v
+		if len(item.Namespace) > 0 {
+			client = v.WithNamespace(item.Namespace)
+		}
+
+		err := client.RevokeLease(ctx, result.secret.LeaseID)
+		if err != nil {
+			logrus.Warnf("unable to revoke lease for secret %s read in dry run: %v", item.Source, err)
+		}
+	}
+
+	return result.secret, nil
+}
+
+// planRead returns whether the secret for the item is read in a dry
+// run, which is only the case for a GET request to a key/value secrets
+// engine as reading from any other secrets engine (i.e. database/creds)
+// may generate credentials.
+func planRead(ctx context.Context, v *vault.Client, item *Item) bool {
+	if item.isWrite() {
+		return false
+	}
+
+	if len(item.Namespace) > 0 {
+		v = v.WithNamespace(item.Namespace)
+	}
+
+	return v.IsKV(ctx, item.Source)
+}
+
+// planCapabilities checks the token is capable of reading the
+// secret for the item without sending the request, as the
+// request may generate credentials.
+func planCapabilities(ctx context.Context, v *vault.Client, i int, item *Item) planResult {
+	result := planResult{Item: i, Source: item.Source, Detail: "capabilities only"}
+
+	if len(item.Namespace) > 0 {
+		v = v.WithNamespace(item.Namespace)
+	}
+
+	capabilities, err := v.Capabilities(ctx, item.Source)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+
+	// a POST request requires the update (or create) capability
+	required := []string{"read"}
+	if item.isWrite() {
+		required = []string{"update", "create"}
+	}
+
+	if !slices.Contains(capabilities, "root") &&
+		!slices.ContainsFunc(required, func(c string) bool { return slices.Contains(capabilities, c) }) {
+		result.Err = fmt.Errorf("token is missing the %s capability (capabilities: %v)", required[0], capabilities)
+	}
+
+	return result
+}
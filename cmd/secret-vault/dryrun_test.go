@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestVault_Read_Exec_DryRun(t *testing.T) {
+	// setup types
+	revoked := []string{}
+
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/app":
+			_, _ = w.Write([]byte(`{"data":{"username":"octocat","password":"superSecretPassword","config":"{\"port\":5432}"}}`))
+		case "/v1/sys/internal/ui/mounts/secret/app", "/v1/sys/internal/ui/mounts/secret/missing":
+			_, _ = w.Write([]byte(`{"data":{"path":"secret/","type":"kv","options":{"version":"1"}}}`))
+		case "/v1/sys/internal/ui/mounts/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"data":{"path":"database/","type":"database"}}`))
+		case "/v1/sys/leases/revoke":
+			revoked = append(revoked, r.URL.Path)
+
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/sys/capabilities-self":
+			body := new(bytes.Buffer)
+			_, _ = body.ReadFrom(r.Body)
+
+			switch {
+			case strings.Contains(body.String(), "aws/sts/deploy"):
+				_, _ = w.Write([]byte(`{"data":{"capabilities":["update"]}}`))
+			case strings.Contains(body.String(), "database/creds/readonly"):
+				_, _ = w.Write([]byte(`{"data":{"capabilities":["read"]}}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":{"capabilities":["deny"]}}`))
+			}
+		case "/v1/database/creds/readonly", "/v1/aws/sts/deploy", "/v1/aws/sts/admin":
+			t.Errorf("dry run generated a dynamic secret at %s", r.URL.Path)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	out := new(bytes.Buffer)
+	planOutput = out
+
+	t.Cleanup(func() { planOutput = os.Stdout })
+
+	r := &Read{
+		DryRun:      true,
+		OutputsPath: "/vela/outputs/.env",
+		Items: []*Item{
+			{
+				Source: "secret/app",
+				Keys: []KeyItem{
+					{Name: "username", Target: []string{"USERNAME"}},
+					{Name: "config", Select: ".port", Path: []string{"port"}},
+				},
+				File:   "app.env",
+				Format: FormatDotenv,
+			},
+			{
+				Source: "database/creds/readonly",
+				Path:   []string{"database"},
+			},
+			{
+				Source: "aws/sts/deploy",
+				Method: MethodPost,
+				Keys:   []KeyItem{{Name: "access_key", Target: []string{"AWS_ACCESS_KEY_ID"}}},
+			},
+			{
+				File:     ".npmrc",
+				Template: "{{ .app.username }}",
+				Sources:  map[string]string{"app": "secret/app"},
+			},
+		},
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err != nil {
+		t.Errorf("Exec returned err: %v\n%s", err, out)
+	}
+
+	if len(revoked) != 0 {
+		t.Errorf("Exec revoked %d leases, want 0", len(revoked))
+	}
+
+	rows := [][]string{
+		{"0", "secret/app", "username", planOK},
+		{"0", "secret/app", "config.port", planOK},
+		{"0", "secret/app", "app.env", planOK, FormatDotenv},
+		{"1", "database/creds/readonly", planOK, "capabilities", "only"},
+		{"2", "aws/sts/deploy", planOK, "capabilities", "only"},
+		{"3", "secret/app", "app", planOK},
+		{"3", ".npmrc", planOK, "template"},
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(rows)+1 {
+		t.Fatalf("Exec results have %d lines, want %d:\n%s", len(lines), len(rows)+1, out)
+	}
+
+	for i, row := range rows {
+		if got := strings.Fields(lines[i+1]); !slices.Equal(got, row) {
+			t.Errorf("Exec result %d is %v, want %v", i, got, row)
+		}
+	}
+
+	for _, value := range []string{"octocat", "superSecretPassword", "5432"} {
+		if strings.Contains(out.String(), value) {
+			t.Errorf("Exec results contain secret value %q:\n%s", value, out)
+		}
+	}
+
+	// verify nothing was written to the filesystem
+	err = afero.Walk(appFS, "/", func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			t.Errorf("Exec wrote file %s", path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unable to walk filesystem: %v", err)
+	}
+
+	// failing checks
+	out.Reset()
+
+	r = &Read{
+		DryRun: true,
+		Items: []*Item{
+			{
+				Source: "secret/app",
+				Keys: []KeyItem{
+					{Name: "missing", Target: []string{"MISSING"}},
+					{Name: "config", Select: ".host", Target: []string{"HOST"}},
+				},
+			},
+			{Source: "secret/missing", Path: []string{"missing"}},
+			{Source: "aws/sts/admin", Method: MethodPost, Path: []string{"aws"}},
+		},
+	}
+
+	err = r.Exec(t.Context(), v)
+	if !errors.Is(err, ErrDryRunFailed) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrDryRunFailed)
+	}
+
+	if got := strings.Count(out.String(), planFailed); got != 4 {
+		t.Errorf("Exec results have %d failed checks, want 4:\n%s", got, out)
+	}
+}
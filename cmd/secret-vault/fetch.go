@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/secret-vault/vault"
+)
+
+// DefaultParallelism defines the default number of
+// items fetched from Vault at the same time.
+const DefaultParallelism = 4
+
+// ErrInvalidParallelism defines the error type when an
+// invalid parallelism was provided for a Vault read.
+var ErrInvalidParallelism = errors.New("invalid `parallelism` provided")
+
+type (
+	// fetcher captures the results of requests to Vault
+	// so identical requests across items are only sent once.
+	fetcher struct {
+		mu      sync.Mutex
+		results map[string]*fetch
+	}
+
+	// fetch represents the result of a single request to Vault.
+	fetch struct {
+		once   sync.Once
+		secret *api.Secret
+		paths  []string
+		err    error
+		// is the item the secret was fetched for
+		item *Item
+		// whether the lease for the secret has been recorded
+		recorded bool
+	}
+)
+
+// get returns the fetch for the key, creating
+// the fetch if it does not exist yet.
+func (f *fetcher) get(key string) *fetch {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.results == nil {
+		f.results = make(map[string]*fetch)
+	}
+
+	result, ok := f.results[key]
+	if !ok {
+		result = new(fetch)
+		f.results[key] = result
+	}
+
+	return result
+}
+
+// reset drops the results of previous requests
+// so the requests are sent to Vault again.
+func (f *fetcher) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.results = nil
+}
+
+// secret returns the secret for the item, only sending
+// the request to Vault for the first identical item.
+func (f *fetcher) secret(ctx context.Context, v *vault.Client, item *Item) *fetch {
+	result := f.get(readKey(item))
+
+	result.once.Do(func() {
+		// override the namespace for the item
+		if len(item.Namespace) > 0 {
+			v = v.WithNamespace(item.Namespace)
+		}
+
+		logrus.Tracef("fetching secret %s", item.Source)
+
+		result.item = item
+
+		if item.isWrite() {
+			result.secret, result.err = v.Write(ctx, item.Source, item.Parameters)
+		} else {
+			result.secret, result.err = v.ReadVersion(ctx, item.Source, item.Version)
+		}
+	})
+
+	return result
+}
+
+// list returns the paths of the secrets matching the glob
+// source for the item, only sending the requests to Vault
+// for the first identical item.
+func (f *fetcher) list(ctx context.Context, v *vault.Client, item *Item) *fetch {
+	base, recursive := globSource(item.Source)
+
+	result := f.get(fmt.Sprintf("list|%s|%s|%t", strings.Trim(item.Namespace, "/"), base, recursive))
+
+	result.once.Do(func() {
+		// override the namespace for the item
+		if len(item.Namespace) > 0 {
+			v = v.WithNamespace(item.Namespace)
+		}
+
+		logrus.Tracef("listing secrets under %s", base)
+
+		result.paths, result.err = v.Walk(ctx, base, recursive)
+	})
+
+	return result
+}
+
+// reset drops the secrets and leases of a previous read so
+// every read sends the requests to Vault again.
+func (r *Read) reset() {
+	r.fetcher.reset()
+
+	r.Leases = nil
+}
+
+// leases records the leases for every secret fetched from Vault,
+// including secrets fetched ahead of an item that failed, so the
+// generated credentials can always be revoked.
+func (r *Read) leases() {
+	r.fetcher.mu.Lock()
+	defer r.fetcher.mu.Unlock()
+
+	// record the leases in a consistent order
+	keys := make([]string, 0, len(r.fetcher.results))
+	for key := range r.fetcher.results {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		r.recordLease(r.fetcher.results[key])
+	}
+}
+
+// readKey returns the key identifying the request to Vault for the item.
+func readKey(item *Item) string {
+	method := MethodGet
+	if item.isWrite() {
+		method = MethodPost
+	}
+
+	// maps are serialized with sorted keys and the
+	// parameters were unmarshaled from JSON already
+	params, _ := json.Marshal(item.Parameters)
+
+	return fmt.Sprintf("read|%s|%s|%s|%d|%s",
+		strings.Trim(item.Namespace, "/"), method, strings.Trim(item.Source, "/"), item.Version, params)
+}
+
+// prefetch sends the requests to Vault for all items using
+// the configured number of workers. The results are captured
+// for reading the items in order, so any errors are returned
+// when the item is read.
+func (r *Read) prefetch(ctx context.Context, v *vault.Client) {
+	parallelism := r.Parallelism
+	if parallelism == 0 {
+		parallelism = DefaultParallelism
+	}
+
+	// fetching items one at a time is handled when reading the items
+	if parallelism == 1 || len(r.Items) < 2 {
+		return
+	}
+
+	logrus.Debugf("fetching %d items with %d workers", len(r.Items), parallelism)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+
+	workers := make(chan struct{}, parallelism)
+
+	for _, item := range r.Items {
+		// stop fetching items after a failure or cancellation
+		// to avoid generating dynamic secrets that are not used
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+
+		if stop || ctx.Err() != nil {
+			break
+		}
+
+		workers <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-workers }()
+
+			if !r.prefetchItem(ctx, v, item) {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+}
+
+// prefetchItem sends the requests to Vault for the item,
+// returning whether all of the requests were successful.
+func (r *Read) prefetchItem(ctx context.Context, v *vault.Client, item *Item) bool {
+	// fetch returns whether the request for the item was successful
+	fetch := func(item *Item) bool {
+		// secrets that may generate credentials are not read in a dry run
+		if r.DryRun && !planRead(ctx, v, item) {
+			return true
+		}
+
+		return r.fetcher.secret(ctx, v, item).err == nil
+	}
+
+	switch {
+	case item.isTemplate():
+		for _, source := range item.Sources {
+			if !fetch(&Item{Source: source, Namespace: item.Namespace}) {
+				return false
+			}
+		}
+	case item.isGlob():
+		list := r.fetcher.list(ctx, v, item)
+		if list.err != nil {
+			return false
+		}
+
+		for _, source := range list.paths {
+			if !fetch(&Item{Source: source, Namespace: item.Namespace}) {
+				return false
+			}
+		}
+	default:
+		return fetch(item)
+	}
+
+	return true
+}
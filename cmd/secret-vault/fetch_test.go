@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestVault_Read_Exec_Parallel(t *testing.T) {
+	// setup types
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+		inflight int
+		peak     int
+	)
+
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		inflight++
+		peak = max(peak, inflight)
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			inflight--
+			mu.Unlock()
+		}()
+
+		// hold the request to allow other requests to be sent
+		time.Sleep(20 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abcd","lease_duration":3600,"data":{"password":"superSecretPassword"}}`))
+		case strings.HasPrefix(r.URL.Path, "/v1/secret/app"):
+			_, _ = fmt.Fprintf(w, `{"data":{"value":"%s"}}`, strings.TrimPrefix(r.URL.Path, "/v1/secret/"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	r := &Read{
+		Parallelism: 3,
+		Items: []*Item{
+			{
+				Source: "database/creds/readonly",
+				Keys:   []KeyItem{{Name: "password", Target: []string{"DB_PASSWORD"}}},
+			},
+			{
+				Source: "/database/creds/readonly",
+				Keys:   []KeyItem{{Name: "password", Target: []string{"DB_PASSWORD_COPY"}}},
+			},
+		},
+	}
+
+	want := map[string]string{
+		"DB_PASSWORD":      "superSecretPassword",
+		"DB_PASSWORD_COPY": "superSecretPassword",
+	}
+
+	for i := range 10 {
+		source := fmt.Sprintf("secret/app%d", i)
+
+		r.Items = append(r.Items, &Item{
+			Source: source,
+			Keys:   []KeyItem{{Name: "value", Target: []string{"VALUE"}}},
+		})
+
+		// the last item read sets the target
+		want["VALUE"] = strings.TrimPrefix(source, "secret/")
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Outputs); diff != "" {
+		t.Errorf("Exec outputs mismatch (-want +got):\n%s", diff)
+	}
+
+	if requests["/v1/database/creds/readonly"] != 1 {
+		t.Errorf("Exec sent %d requests for identical sources, want 1", requests["/v1/database/creds/readonly"])
+	}
+
+	if len(r.Leases) != 1 {
+		t.Errorf("Exec recorded %d leases, want 1", len(r.Leases))
+	}
+
+	if peak < 2 || peak > r.Parallelism {
+		t.Errorf("Exec sent %d requests at the same time, want between 2 and %d", peak, r.Parallelism)
+	}
+}
+
+func TestVault_Read_Exec_ParallelFailure(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/foo", "/v1/secret/bar":
+			_, _ = w.Write([]byte(`{"data":{"value":"superSecretValue"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	r := &Read{
+		Items: []*Item{
+			{Source: "secret/foo", Path: []string{"foo"}},
+			{Source: "secret/missing", Path: []string{"missing"}},
+			{Source: "secret/bar", Path: []string{"bar"}},
+		},
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err == nil || !strings.Contains(err.Error(), "secret/missing") {
+		t.Errorf("Exec returned err %v, want error for secret/missing", err)
+	}
+}
+
+func TestVault_Read_Exec_ParallelFailure_Lease(t *testing.T) {
+	// setup types
+	fetched := make(chan struct{})
+
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/database/creds/readonly":
+			close(fetched)
+
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abcd","lease_duration":3600,"data":{"password":"superSecretPassword"}}`))
+		case "/v1/secret/missing":
+			// fail the first item once the dynamic secret was generated
+			<-fetched
+
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+	a := &afero.Afero{Fs: appFS}
+
+	r := &Read{
+		Items: []*Item{
+			{Source: "secret/missing", Path: []string{"missing"}},
+			{
+				Source: "database/creds/readonly",
+				Keys:   []KeyItem{{Name: "password", Target: []string{"DB_PASSWORD"}}},
+			},
+		},
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err == nil || !strings.Contains(err.Error(), "secret/missing") {
+		t.Fatalf("Exec returned err %v, want error for secret/missing", err)
+	}
+
+	// the lease of the item never read is recorded to be revoked
+	m, err := readManifest(a, "/vela/secrets/"+LeaseManifest)
+	if err != nil {
+		t.Fatalf("readManifest returned err: %v", err)
+	}
+
+	if len(m.Leases) != 1 || m.Leases[0].ID != "database/creds/readonly/abcd" {
+		t.Errorf("Exec recorded leases %v, want database/creds/readonly/abcd", m.Leases)
+	}
+}
+
+func TestVault_Read_Exec_Repeated(t *testing.T) {
+	// setup types
+	var requests atomic.Int32
+
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/database/creds/readonly":
+			n := requests.Add(1)
+
+			_, _ = fmt.Fprintf(w, `{"lease_id":"database/creds/readonly/%d","lease_duration":3600,"data":{"password":"password%d"}}`, n, n)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+	a := &afero.Afero{Fs: appFS}
+
+	r := &Read{
+		Items: []*Item{
+			{
+				Source: "database/creds/readonly",
+				Keys:   []KeyItem{{Name: "password", Path: []string{"password"}}},
+			},
+		},
+	}
+
+	// run test
+	for range 2 {
+		err := r.Exec(t.Context(), v)
+		if err != nil {
+			t.Fatalf("Exec returned err: %v", err)
+		}
+	}
+
+	// the secret is read again for every read
+	data, _ := a.ReadFile("/vela/secrets/password")
+	if string(data) != "password2" {
+		t.Errorf("Exec wrote %s, want password2", data)
+	}
+
+	// the leases of the first read are only recorded once
+	m, err := readManifest(a, "/vela/secrets/"+LeaseManifest)
+	if err != nil {
+		t.Fatalf("readManifest returned err: %v", err)
+	}
+
+	got := []string{}
+	for _, lease := range m.Leases {
+		got = append(got, lease.ID)
+	}
+
+	want := []string{"database/creds/readonly/1", "database/creds/readonly/2"}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Exec leases mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_Read_Exec_Canceled(t *testing.T) {
+	// setup types
+	var requests atomic.Int32
+
+	v := newTestVault(t, func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	r := &Read{
+		Items: []*Item{
+			{Source: "secret/foo", Path: []string{"foo"}},
+			{Source: "secret/bar", Path: []string{"bar"}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	// run test
+	err := r.Exec(ctx, v)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Exec returned err %v, want %v", err, context.Canceled)
+	}
+
+	if requests.Load() != 0 {
+		t.Errorf("Exec sent %d requests, want 0", requests.Load())
+	}
+}
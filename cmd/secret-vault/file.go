@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// defaultFileMode defines the mode for secret files
+	// when no mode was provided for an item or key.
+	defaultFileMode os.FileMode = 0600
+
+	// defaultDirMode defines the mode for directories created
+	// for secret files when no dir_mode was provided, which is
+	// reduced by the umask of the plugin.
+	defaultDirMode os.FileMode = 0777
+)
+
+var (
+	// ErrInvalidMode defines the error type when an invalid
+	// mode or dir_mode was provided for a Vault read.
+	ErrInvalidMode = errors.New("invalid `mode` or `dir_mode` provided")
+
+	// ErrInvalidOwner defines the error type when an invalid
+	// uid or gid was provided for a Vault read.
+	ErrInvalidOwner = errors.New("invalid `uid` or `gid` provided")
+)
+
+// FileOptions represents the mode and ownership of the files
+// written for an item or key and the directories created for them.
+type FileOptions struct {
+	// is the octal mode of the files (i.e. 0640)
+	Mode string `json:"mode"`
+	// is the octal mode of the directories created (i.e. 0750)
+	DirMode string `json:"dir_mode"`
+	// is the user ID to own the files and directories created
+	UID *int `json:"uid"`
+	// is the group ID to own the files and directories created
+	GID *int `json:"gid"`
+}
+
+// merge returns the options with any option that was
+// not provided inherited from the parent options.
+func (o FileOptions) merge(parent FileOptions) FileOptions {
+	if len(o.Mode) == 0 {
+		o.Mode = parent.Mode
+	}
+
+	if len(o.DirMode) == 0 {
+		o.DirMode = parent.DirMode
+	}
+
+	if o.UID == nil {
+		o.UID = parent.UID
+	}
+
+	if o.GID == nil {
+		o.GID = parent.GID
+	}
+
+	return o
+}
+
+// Validate verifies the file options are properly configured.
+func (o FileOptions) Validate() error {
+	for _, mode := range []string{o.Mode, o.DirMode} {
+		if len(mode) == 0 {
+			continue
+		}
+
+		_, err := parseMode(mode)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidMode, err)
+		}
+	}
+
+	for _, id := range []*int{o.UID, o.GID} {
+		if id != nil && *id < 0 {
+			return fmt.Errorf("%w: %d", ErrInvalidOwner, *id)
+		}
+	}
+
+	return nil
+}
+
+// owned returns whether an owner was provided for the files.
+func (o FileOptions) owned() bool {
+	return o.UID != nil || o.GID != nil
+}
+
+// owner returns the user and group ID to own the files,
+// using -1 for either that was not provided to keep it.
+func (o FileOptions) owner() (int, int) {
+	uid, gid := -1, -1
+
+	if o.UID != nil {
+		uid = *o.UID
+	}
+
+	if o.GID != nil {
+		gid = *o.GID
+	}
+
+	return uid, gid
+}
+
+// parseMode parses the octal permission bits from the mode.
+func parseMode(mode string) (os.FileMode, error) {
+	m, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not an octal mode", mode)
+	}
+
+	if m > uint64(os.ModePerm) {
+		return 0, fmt.Errorf("%s is not a permission mode (0000-0777)", mode)
+	}
+
+	return os.FileMode(m), nil
+}
+
+// writeFile writes the data to the file at the provided path,
+// creating any missing directories, with the mode and ownership
+// from the provided options.
+func writeFile(a *afero.Afero, path string, data []byte, opts FileOptions) error {
+	dir := filepath.Dir(path)
+
+	// capture the directories that will be created for the file
+	created := []string{}
+
+	for d := dir; ; d = filepath.Dir(d) {
+		exists, err := a.DirExists(d)
+		if err != nil || exists || d == filepath.Dir(d) {
+			break
+		}
+
+		created = append(created, d)
+	}
+
+	dirMode := defaultDirMode
+
+	if len(opts.DirMode) > 0 {
+		dirMode, _ = parseMode(opts.DirMode)
+	}
+
+	// send Filesystem call to create directory path for the file
+	logrus.Tracef("creating directories in path %s", path)
+
+	err := a.MkdirAll(dir, dirMode)
+	if err != nil {
+		return err
+	}
+
+	uid, gid := opts.owner()
+
+	for _, d := range created {
+		// set the mode explicitly as the umask applies when creating
+		if len(opts.DirMode) > 0 {
+			err = a.Chmod(d, dirMode)
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.owned() {
+			err = a.Chown(d, uid, gid)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// set the secret in the Vela temp build volume
+	logrus.Tracef("write data to file %s", path)
+
+	err = a.WriteFile(path, data, defaultFileMode)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Mode) > 0 {
+		mode, _ := parseMode(opts.Mode)
+
+		err = a.Chmod(path, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.owned() {
+		return a.Chown(path, uid, gid)
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+// ownerFs records the owners set for paths as the
+// in-memory filesystem does not report them.
+type ownerFs struct {
+	afero.Fs
+
+	owners map[string][2]int
+}
+
+func (f *ownerFs) Chown(name string, uid, gid int) error {
+	f.owners[filepath.Clean(name)] = [2]int{uid, gid}
+
+	return f.Fs.Chown(name, uid, gid)
+}
+
+func (f *ownerFs) Rename(oldname, newname string) error {
+	if owner, ok := f.owners[filepath.Clean(oldname)]; ok {
+		delete(f.owners, filepath.Clean(oldname))
+
+		f.owners[filepath.Clean(newname)] = owner
+	}
+
+	return f.Fs.Rename(oldname, newname)
+}
+
+func TestVault_Read_Unmarshal_FileOptions(t *testing.T) {
+	// setup types
+	uid, gid := 1000, 2000
+
+	r := &Read{
+		RawItems: `[{"source":"secret/foo","mode":"0640","dir_mode":"0750","uid":1000,"gid":2000,` +
+			`"keys":[{"name":"password","path":"db/password","mode":"0400"}]}]`,
+	}
+
+	want := []*Item{
+		{
+			Source: "secret/foo",
+			FileOptions: FileOptions{
+				Mode:    "0640",
+				DirMode: "0750",
+				UID:     &uid,
+				GID:     &gid,
+			},
+			Keys: []KeyItem{
+				{
+					Name:        "password",
+					Path:        []string{"db/password"},
+					FileOptions: FileOptions{Mode: "0400"},
+				},
+			},
+		},
+	}
+
+	// run test
+	err := r.Unmarshal()
+	if err != nil {
+		t.Fatalf("Unmarshal returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Items); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_Read_Validate_FileOptions(t *testing.T) {
+	// setup types
+	negative := -1
+
+	tests := []struct {
+		name string
+		opts FileOptions
+		err  error
+	}{
+		{name: "valid", opts: FileOptions{Mode: "0640", DirMode: "750"}},
+		{name: "not octal", opts: FileOptions{Mode: "0999"}, err: ErrInvalidMode},
+		{name: "special bits", opts: FileOptions{DirMode: "01777"}, err: ErrInvalidMode},
+		{name: "negative uid", opts: FileOptions{UID: &negative}, err: ErrInvalidOwner},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Read{
+				Items: []*Item{
+					{
+						Source: "secret/foo",
+						Keys: []KeyItem{
+							{Name: "password", Path: []string{"password"}, FileOptions: test.opts},
+						},
+					},
+				},
+			}
+
+			err := r.Validate()
+			if !errors.Is(err, test.err) {
+				t.Errorf("Validate returned err %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestVault_Read_Exec_FileOptions(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/foo":
+			_, _ = w.Write([]byte(`{"data":{"username":"admin","password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	uid, gid := 1000, 2000
+
+	// setup filesystem
+	fs := &ownerFs{Fs: afero.NewMemMapFs(), owners: make(map[string][2]int)}
+	appFS = fs
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.MkdirAll("/vela/secrets", 0755)
+	if err != nil {
+		t.Fatalf("unable to create secrets directory: %v", err)
+	}
+
+	r := &Read{
+		Items: []*Item{
+			{
+				Source:      "secret/foo",
+				FileOptions: FileOptions{Mode: "0640", DirMode: "0750", UID: &uid, GID: &gid},
+				Keys: []KeyItem{
+					{Name: "username", Path: []string{"app/db/username"}},
+					{Name: "password", Path: []string{"app/db/password"}, FileOptions: FileOptions{Mode: "0400"}},
+				},
+			},
+			{
+				Source: "secret/foo",
+				File:   "app.env",
+				Format: FormatDotenv,
+			},
+		},
+	}
+
+	wantModes := map[string]os.FileMode{
+		"/vela/secrets":                 0755,
+		"/vela/secrets/app":             0750,
+		"/vela/secrets/app/db":          0750,
+		"/vela/secrets/app/db/password": 0400,
+		"/vela/secrets/app/db/username": 0640,
+		"/vela/secrets/app.env":         0600,
+	}
+
+	wantOwners := map[string][2]int{
+		"/vela/secrets/app":             {uid, gid},
+		"/vela/secrets/app/db":          {uid, gid},
+		"/vela/secrets/app/db/password": {uid, gid},
+		"/vela/secrets/app/db/username": {uid, gid},
+	}
+
+	// run test
+	err = r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	gotModes := make(map[string]os.FileMode)
+
+	err = a.Walk("/vela/secrets", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		gotModes[path] = info.Mode().Perm()
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(wantModes, gotModes); diff != "" {
+		t.Errorf("Exec modes mismatch (-want +got):\n%s", diff)
+	}
+
+	// the owner is set on the temporary file before it is renamed
+	gotOwners := fs.owners
+
+	if diff := cmp.Diff(wantOwners, gotOwners); diff != "" {
+		t.Errorf("Exec owners mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -16,6 +16,34 @@ func flags() []cli.Flag {
 			Usage:   "list of items to extract from a Vault",
 			Sources: cli.EnvVars("PARAMETER_ITEMS", "ITEMS"),
 		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Usage:   "check access to the secrets without writing them",
+			Sources: cli.EnvVars("PARAMETER_DRY_RUN", "DRY_RUN"),
+		},
+		&cli.IntFlag{
+			Name:    "parallelism",
+			Usage:   "number of items to fetch from a Vault at the same time",
+			Sources: cli.EnvVars("PARAMETER_PARALLELISM", "PARALLELISM"),
+			Value:   DefaultParallelism,
+		},
+		&cli.StringFlag{
+			Name:    "on-outputs-error",
+			Usage:   "how to handle failing to write the outputs file (warn or fail)",
+			Sources: cli.EnvVars("PARAMETER_ON_OUTPUTS_ERROR", "ON_OUTPUTS_ERROR"),
+		},
+		&cli.BoolFlag{
+			Name:    "strict-outputs",
+			Usage:   "fail when the outputs file can not be written",
+			Sources: cli.EnvVars("PARAMETER_STRICT_OUTPUTS", "STRICT_OUTPUTS"),
+			Value:   true,
+		},
+		&cli.StringFlag{
+			Name:    "secrets-root",
+			Usage:   "directory of the volume to write secrets to",
+			Sources: cli.EnvVars("PARAMETER_SECRETS_ROOT", "SECRETS_ROOT"),
+			Value:   DefaultSecretsRoot,
+		},
 		&cli.StringFlag{
 			Sources: cli.EnvVars("VELA_MASKED_BASE64_OUTPUTS"),
 			Name:    "vela.masked-outputs",
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/secret-vault/vault"
+)
+
+const (
+	// globSecrets defines the pattern for matching
+	// the secrets directly under a path.
+	globSecrets = "*"
+
+	// globRecursive defines the pattern for matching
+	// all the secrets under a path recursively.
+	globRecursive = "**"
+)
+
+var (
+	// ErrInvalidGlob defines the error type when an
+	// invalid glob source was provided for a Vault read.
+	ErrInvalidGlob = errors.New("invalid glob `source` provided")
+
+	// ErrNoSecretsFound defines the error type when no
+	// secrets were found for a glob source.
+	ErrNoSecretsFound = errors.New("no secrets found")
+)
+
+// isGlob returns whether the item source matches several secrets.
+func (i *Item) isGlob() bool {
+	return strings.Contains(i.Source, globSecrets)
+}
+
+// globSource returns the path the secrets are listed
+// under for the glob source and whether the secrets
+// are listed recursively.
+func globSource(source string) (string, bool) {
+	base, pattern := path.Split(strings.TrimPrefix(source, "/"))
+
+	return strings.TrimSuffix(base, "/"), pattern == globRecursive
+}
+
+// execGlobItem reads each of the secrets matching the glob `source` and
+// writes the data keys to the /vela/secrets/<path>/<relative path>/<key> file,
+// mirroring the tree of secrets under the source.
+//
+// it also populates the outputs map with the key of <PREFIX>_<RELATIVE PATH>_<KEY>
+// when a target prefix is configured.
+func (r *Read) execGlobItem(ctx context.Context, v *vault.Client, a *afero.Afero, item *Item) error {
+	base, _ := globSource(item.Source)
+
+	list := r.fetcher.list(ctx, v, item)
+	if list.err != nil {
+		return list.err
+	}
+
+	sources := list.paths
+
+	if len(sources) == 0 {
+		return fmt.Errorf("%w for source %s", ErrNoSecretsFound, item.Source)
+	}
+
+	for _, source := range sources {
+		// read data from the vault provider for the secret
+		secret, err := r.readItem(ctx, v, &Item{
+			Source:    source,
+			Namespace: item.Namespace,
+		})
+		if err != nil {
+			return err
+		}
+
+		rel := strings.TrimPrefix(source, base+"/")
+
+		for _, pth := range item.Path {
+			logrus.Tracef("writing data from source %s", source)
+
+			// remove any leading and trailing slashes from path
+			p := strings.Trim(pth, "/")
+
+			err := r.writeLegacySecretFiles(a, path.Join(p, rel), secret.Data, item.FileOptions)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(item.TargetPrefix) > 0 {
+			for k, data := range secret.Data {
+				value, err := formatValue(data, "")
+				if err != nil {
+					return fmt.Errorf("unable to format key %s in vault secret at %s: %w", k, source, err)
+				}
+
+				envKey := sanitizeEnvKey(strings.ToUpper(item.TargetPrefix + "_" + rel + "_" + k))
+
+				r.Outputs[envKey] = value
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateGlobItem verifies the glob item is properly configured.
+func validateGlobItem(i int, item *Item) error {
+	base, pattern := path.Split(strings.TrimPrefix(item.Source, "/"))
+
+	// verify the glob is only provided as the last segment
+	if len(strings.Trim(base, "/")) == 0 || strings.Contains(base, globSecrets) ||
+		(pattern != globSecrets && pattern != globRecursive) {
+		return fmt.Errorf("%w for item %d: %s (must end with /%s or /%s)", ErrInvalidGlob, i, item.Source, globSecrets, globRecursive)
+	}
+
+	// verify that at least one non-empty path or a target prefix was provided
+	if len(strings.Join(item.Path, "")) == 0 && len(item.TargetPrefix) == 0 {
+		return fmt.Errorf("%w for item %d: `path` or `target_prefix` required", ErrInvalidGlob, i)
+	}
+
+	// verify the glob is not combined with other modes
+	if len(item.Keys) > 0 || len(item.File) > 0 || item.DockerAuth != nil {
+		return fmt.Errorf("%w for item %d: `keys`, `file` and `docker_auth` not allowed", ErrInvalidGlob, i)
+	}
+
+	// verify the glob is only used for reading secrets
+	if item.Version > 0 || item.isWrite() {
+		return fmt.Errorf("%w for item %d: `version`, `method` %s and `parameters` not allowed", ErrInvalidGlob, i, MethodPost)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestVault_Read_Exec_Glob(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		key := r.URL.Path
+		if len(r.URL.RawQuery) > 0 {
+			key += "?" + r.URL.RawQuery
+		}
+
+		switch key {
+		case "/v1/secret/team/app?list=true":
+			_, _ = w.Write([]byte(`{"data":{"keys":["api","db/"]}}`))
+		case "/v1/secret/team/app/db?list=true":
+			_, _ = w.Write([]byte(`{"data":{"keys":["primary"]}}`))
+		case "/v1/secret/team/app/api":
+			_, _ = w.Write([]byte(`{"data":{"token":"superSecretToken"}}`))
+		case "/v1/secret/team/app/db/primary":
+			_, _ = w.Write([]byte(`{"data":{"password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// setup tests
+	tests := []struct {
+		source  string
+		files   map[string]string
+		outputs map[string]string
+	}{
+		{
+			source: "secret/team/app/*",
+			files: map[string]string{
+				"/vela/secrets/app/api/token": "superSecretToken",
+			},
+			outputs: map[string]string{
+				"APP_API_TOKEN": "superSecretToken",
+			},
+		},
+		{
+			source: "secret/team/app/**",
+			files: map[string]string{
+				"/vela/secrets/app/api/token":           "superSecretToken",
+				"/vela/secrets/app/db/primary/password": "superSecretPassword",
+			},
+			outputs: map[string]string{
+				"APP_API_TOKEN":           "superSecretToken",
+				"APP_DB_PRIMARY_PASSWORD": "superSecretPassword",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		r := &Read{
+			Items: []*Item{
+				{
+					Source:       test.source,
+					Path:         []string{"app"},
+					TargetPrefix: "app",
+				},
+			},
+		}
+
+		err := r.Validate()
+		if err != nil {
+			t.Errorf("Validate for %s returned err: %v", test.source, err)
+		}
+
+		err = r.Exec(t.Context(), v)
+		if err != nil {
+			t.Errorf("Exec for %s returned err: %v", test.source, err)
+
+			continue
+		}
+
+		for path, want := range test.files {
+			got, err := a.ReadFile(path)
+			if err != nil {
+				t.Errorf("unable to read secret file %s: %v", path, err)
+
+				continue
+			}
+
+			if string(got) != want {
+				t.Errorf("Exec for %s file %s is %q, want %q", test.source, path, got, want)
+			}
+		}
+
+		if diff := cmp.Diff(test.outputs, r.Outputs); diff != "" {
+			t.Errorf("Exec for %s outputs mismatch (-want +got):\n%s", test.source, diff)
+		}
+	}
+
+	// no secrets found
+	r := &Read{
+		Items: []*Item{{Source: "secret/team/missing/**", Path: []string{"missing"}}},
+	}
+
+	err := r.Exec(t.Context(), v)
+	if !errors.Is(err, ErrNoSecretsFound) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrNoSecretsFound)
+	}
+}
+
+func TestVault_Read_Validate_Glob(t *testing.T) {
+	// setup types
+	tests := []*Item{
+		{Source: "secret/*/app/*", Path: []string{"app"}},
+		{Source: "secret/team/app*", Path: []string{"app"}},
+		{Source: "*", Path: []string{"app"}},
+		{Source: "secret/team/app/*"},
+		{Source: "secret/team/app/*", Path: []string{""}},
+		{Source: "secret/team/app/*", Path: []string{"app"}, File: "app.env", Format: FormatDotenv},
+		{Source: "secret/team/app/*", Path: []string{"app"}, Version: 2},
+		{Source: "secret/team/app", Path: []string{"app"}, TargetPrefix: "app"},
+	}
+
+	// run test
+	for _, test := range tests {
+		err := (&Read{Items: []*Item{test}}).Validate()
+		if !errors.Is(err, ErrInvalidGlob) {
+			t.Errorf("Validate for %s returned err %v, want %v", test.Source, err, ErrInvalidGlob)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// LeaseManifest defines the file in the secrets volume that stores
+// the leases for dynamic secrets read during a build execution.
+var LeaseManifest = ".vault-leases.json"
+
+type (
+	// Manifest represents the leases captured during a build
+	// execution so a later step is able to revoke them.
+	Manifest struct {
+		// leases for the dynamic secrets read
+		Leases []*Lease `json:"leases"`
+		// accessors for the tokens created when logging in
+		Accessors []string `json:"token_accessors,omitempty"`
+	}
+
+	// Lease represents the lease for a dynamic secret.
+	Lease struct {
+		// is the ID of the lease for the secret
+		ID string `json:"lease_id"`
+		// is the duration of the lease for the secret in seconds
+		Duration int `json:"lease_duration"`
+		// is whether the lease for the secret is renewable
+		Renewable bool `json:"renewable"`
+		// is the path to where the secret was read from in Vault
+		Source string `json:"source"`
+		// is the Vault Enterprise namespace the secret was read from
+		Namespace string `json:"namespace,omitempty"`
+	}
+)
+
+// readManifest captures the lease manifest from the provided path,
+// returning an empty manifest when the file does not exist.
+func readManifest(a *afero.Afero, path string) (*Manifest, error) {
+	m := new(Manifest)
+
+	data, err := a.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+
+		return nil, fmt.Errorf("unable to read lease manifest %s: %w", path, err)
+	}
+
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse lease manifest %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// writeManifest writes the lease manifest to the provided path.
+func writeManifest(a *afero.Afero, path string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize lease manifest: %w", err)
+	}
+
+	// send Filesystem call to create directory path for manifest file
+	logrus.Tracef("creating directories in path %s", path)
+
+	err = a.MkdirAll(filepath.Dir(path), 0777)
+	if err != nil {
+		return err
+	}
+
+	logrus.Tracef("write lease manifest to file %s", path)
+
+	return a.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestVault_Manifest(t *testing.T) {
+	// setup filesystem
+	a := &afero.Afero{
+		Fs: afero.NewMemMapFs(),
+	}
+
+	path := "/vela/secrets/.vault-leases.json"
+
+	// run test
+	got, err := readManifest(a, path)
+	if err != nil {
+		t.Errorf("readManifest returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(new(Manifest), got); diff != "" {
+		t.Errorf("readManifest mismatch (-want +got):\n%s", diff)
+	}
+
+	want := &Manifest{
+		Leases: []*Lease{
+			{
+				ID:        "database/creds/readonly/abcd",
+				Duration:  3600,
+				Renewable: true,
+				Source:    "database/creds/readonly",
+			},
+		},
+	}
+
+	err = writeManifest(a, path, want)
+	if err != nil {
+		t.Errorf("writeManifest returned err: %v", err)
+	}
+
+	got, err = readManifest(a, path)
+	if err != nil {
+		t.Errorf("readManifest returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("readManifest mismatch (-want +got):\n%s", diff)
+	}
+
+	err = a.WriteFile(path, []byte("not json"), 0600)
+	if err != nil {
+		t.Errorf("unable to write manifest: %v", err)
+	}
+
+	_, err = readManifest(a, path)
+	if err == nil {
+		t.Errorf("readManifest should have returned err")
+	}
+}
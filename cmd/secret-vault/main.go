@@ -7,6 +7,8 @@ import (
 	"log"
 	"net/mail"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
@@ -27,17 +29,92 @@ func main() {
 		},
 		Action: run,
 		Flags:  flags(),
+		Commands: []*cli.Command{
+			{
+				Name:   "revoke",
+				Usage:  "revoke the leases and tokens recorded by a previous read",
+				Action: revoke,
+			},
+		},
 	}
 
+	// cancel in-flight requests when the plugin is interrupted or terminated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
 	// Plugin Start
-	err := app.Run(context.Background(), os.Args)
+	err := app.Run(ctx, os.Args)
+
+	stop()
+
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
 // run executes the plugin based off the configuration provided.
-func run(_ context.Context, c *cli.Command) error {
+func run(ctx context.Context, c *cli.Command) error {
+	setLogLevel(c)
+
+	logrus.WithFields(logrus.Fields{
+		"code":     "https://github.com/go-vela/secret-vault",
+		"docs":     "https://go-vela.github.io/docs/plugins/registry/secret/vault/",
+		"registry": "https://hub.docker.com/r/target/secret-vela",
+	}).Info("Vela Secret Vault Plugin")
+
+	// setup plugin
+	p := Plugin{
+		Config: config(c),
+		Read: &Read{
+			RawItems:       c.String("items"),
+			OutputsPath:    c.String("vela.masked-outputs"),
+			OnOutputsError: c.String("on-outputs-error"),
+			StrictOutputs:  c.Bool("strict-outputs"),
+			SecretsRoot:    c.String("secrets-root"),
+			Parallelism:    c.Int("parallelism"),
+			DryRun:         c.Bool("dry-run"),
+		},
+	}
+
+	// validate the plugin configuration
+	err := p.Validate()
+	if err != nil {
+		return err
+	}
+
+	// execute the plugin
+	return p.Exec(ctx)
+}
+
+// revoke executes the plugin cleanup based off the configuration provided.
+func revoke(ctx context.Context, c *cli.Command) error {
+	setLogLevel(c)
+
+	logrus.WithFields(logrus.Fields{
+		"code":     "https://github.com/go-vela/secret-vault",
+		"docs":     "https://go-vela.github.io/docs/plugins/registry/secret/vault/",
+		"registry": "https://hub.docker.com/r/target/secret-vela",
+	}).Info("Vela Secret Vault Plugin (revoke)")
+
+	// setup plugin
+	p := Plugin{
+		Config: config(c),
+		Revoke: &Revoke{
+			SecretsRoot: c.String("secrets-root"),
+		},
+	}
+
+	// validate the plugin configuration
+	err := p.Config.Validate()
+	if err != nil {
+		return err
+	}
+
+	// execute the plugin cleanup
+	return p.Cleanup(ctx)
+}
+
+// setLogLevel sets the log level for the plugin.
+func setLogLevel(c *cli.Command) {
 	// set the log level for the plugin
 	switch c.String("log.level") {
 	case "t", "trace", "Trace", "TRACE":
@@ -57,34 +134,35 @@ func run(_ context.Context, c *cli.Command) error {
 	default:
 		logrus.SetLevel(logrus.InfoLevel)
 	}
+}
 
-	logrus.WithFields(logrus.Fields{
-		"code":     "https://github.com/go-vela/secret-vault",
-		"docs":     "https://go-vela.github.io/docs/plugins/registry/secret/vault/",
-		"registry": "https://hub.docker.com/r/target/secret-vela",
-	}).Info("Vela Secret Vault Plugin")
-
-	// setup plugin
-	p := Plugin{
-		Config: &Config{
-			Addr:       c.String("config.addr"),
-			AuthMethod: c.String("config.auth-method"),
-			Password:   c.String("config.password"),
-			Token:      c.String("config.token"),
-			Username:   c.String("config.username"),
-		},
-		Read: &Read{
-			RawItems:    c.String("items"),
-			OutputsPath: c.String("vela.masked-outputs"),
-		},
-	}
-
-	// validate the plugin configuration
-	err := p.Validate()
-	if err != nil {
-		return err
+// config creates the plugin Vault configuration from the flags provided.
+func config(c *cli.Command) *Config {
+	return &Config{
+		Addr:                c.String("config.addr"),
+		AuthMethod:          c.String("config.auth-method"),
+		AuthMount:           c.String("config.auth-mount"),
+		CACert:              c.String("config.ca-cert"),
+		CAPath:              c.String("config.ca-path"),
+		ClientCert:          c.String("config.client-cert"),
+		ClientKey:           c.String("config.client-key"),
+		Insecure:            c.Bool("config.insecure"),
+		JWT:                 c.String("config.jwt"),
+		JWTFile:             c.String("config.jwt-file"),
+		KubernetesTokenPath: c.String("config.kubernetes-token-path"),
+		Namespace:           c.String("config.namespace"),
+		Password:            c.String("config.password"),
+		Role:                c.String("config.role"),
+		RoleID:              c.String("config.role-id"),
+		SecretID:            c.String("config.secret-id"),
+		RequestTimeout:      c.Duration("config.request-timeout"),
+		RetryAttempts:       c.Int("config.retry-attempts"),
+		RetryBackoff:        c.Duration("config.retry-backoff"),
+		RetryJitter:         c.Float("config.retry-jitter"),
+		Timeout:             c.Duration("config.timeout"),
+		TLSServerName:       c.String("config.tls-server-name"),
+		Token:               c.String("config.token"),
+		Username:            c.String("config.username"),
+		WrappedSecretID:     c.String("config.wrapped-secret-id"),
 	}
-
-	// execute the plugin
-	return p.Exec()
 }
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// OutputsErrorFail defines the handling that fails the
+	// plugin when the outputs file can not be written.
+	OutputsErrorFail = "fail"
+
+	// OutputsErrorWarn defines the handling that logs a warning
+	// and continues when the outputs file can not be written.
+	OutputsErrorWarn = "warn"
+)
+
+// ErrInvalidOnOutputsError defines the error type when an
+// invalid on_outputs_error was provided for a Vault read.
+var ErrInvalidOnOutputsError = errors.New("invalid `on_outputs_error` provided")
+
+// ErrNoOutputsPath defines the error type when outputs were
+// produced for a Vault read without an outputs file to write to.
+var ErrNoOutputsPath = errors.New("no outputs file provided")
+
+// OutputsError represents a failure to write the outputs file.
+//
+// the error only reports the path and the cause of the failure
+// as the underlying error may contain the secret values.
+type OutputsError struct {
+	// is the path to the outputs file
+	Path string
+	// is the error returned when writing the outputs file
+	Err error
+}
+
+// Error returns the redacted error message.
+func (e *OutputsError) Error() string {
+	if len(e.Path) == 0 {
+		return fmt.Sprintf("unable to write outputs: %v", e.Err)
+	}
+
+	msg := fmt.Sprintf("unable to write outputs file %s", e.Path)
+
+	var pathErr *os.PathError
+	if errors.As(e.Err, &pathErr) {
+		msg += ": " + pathErr.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the error returned when writing the outputs file.
+func (e *OutputsError) Unwrap() error {
+	return e.Err
+}
+
+// stageOutputs renders the outputs to the outputs file in the
+// stage, returning whether the outputs file was staged.
+func (r *Read) stageOutputs(s *stage) (bool, error) {
+	data, err := renderEnvFile(r.Outputs)
+	if err != nil {
+		return false, err
+	}
+
+	err = afero.WriteFile(s.fs, r.OutputsPath, data, 0600)
+	if err != nil {
+		// drop any partially staged outputs file
+		_ = s.remove(r.OutputsPath)
+
+		return false, r.outputsError(err)
+	}
+
+	return true, nil
+}
+
+// commit commits the staged secrets and outputs file together. When
+// the outputs file can not be written and the plugin is configured
+// to warn on outputs errors, the secrets are committed without it.
+func (r *Read) commit(s *stage, outputs bool) error {
+	err := s.commit()
+
+	var commitErr *commitError
+	if outputs && errors.As(err, &commitErr) && commitErr.Path == filepath.Clean(r.OutputsPath) {
+		outputs = false
+
+		err = r.outputsError(commitErr.Err)
+		if err == nil {
+			// the failed commit was rolled back so it can be retried
+			_ = s.remove(r.OutputsPath)
+
+			err = s.commit()
+		}
+	}
+
+	if err == nil && outputs {
+		logrus.Info("successfully wrote secrets to outputs file")
+	}
+
+	return err
+}
+
+// outputsError returns the error for the failure to write the
+// outputs file, only failing the plugin when configured to fail
+// on outputs errors.
+func (r *Read) outputsError(err error) error {
+	err = &OutputsError{Path: r.OutputsPath, Err: err}
+
+	if r.onOutputsError() == OutputsErrorFail {
+		return err
+	}
+
+	logrus.Warnf("%v. values will not be masked if accidentally logged, nor will they be available in the environment.", err)
+
+	return nil
+}
+
+// onOutputsError returns how to handle failing to write the outputs
+// file. An explicit on_outputs_error takes precedence, otherwise the
+// plugin fails unless strict_outputs was turned off.
+func (r *Read) onOutputsError() string {
+	if len(r.OnOutputsError) > 0 {
+		return r.OnOutputsError
+	}
+
+	if r.StrictOutputs {
+		return OutputsErrorFail
+	}
+
+	return OutputsErrorWarn
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v3"
+)
+
+func TestVault_Read_Exec_OutputsError(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/foo":
+			_, _ = w.Write([]byte(`{"data":{"password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		onOutputsError string
+		strictOutputs  bool
+		failure        bool
+	}{
+		{name: "default", onOutputsError: "", strictOutputs: strictOutputsDefault(t), failure: true},
+		{name: "not strict", onOutputsError: "", failure: false},
+		{name: "fail", onOutputsError: OutputsErrorFail, failure: true},
+		{name: "warn", onOutputsError: OutputsErrorWarn, failure: false},
+		{name: "strict warn", onOutputsError: OutputsErrorWarn, strictOutputs: true, failure: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup filesystem without the outputs directory
+			appFS = newTestFS(t)
+			a := &afero.Afero{Fs: appFS}
+
+			r := &Read{
+				OutputsPath:    "/vela/outputs/.env",
+				OnOutputsError: test.onOutputsError,
+				StrictOutputs:  test.strictOutputs,
+				Items: []*Item{
+					{
+						Source: "secret/foo",
+						Keys:   []KeyItem{{Name: "password", Path: []string{"password"}, Target: []string{"PASSWORD"}}},
+					},
+				},
+			}
+
+			err := r.Exec(t.Context(), v)
+
+			if test.failure {
+				var outputsErr *OutputsError
+				if !errors.As(err, &outputsErr) {
+					t.Fatalf("Exec returned err %v, want %T", err, outputsErr)
+				}
+
+				if outputsErr.Path != r.OutputsPath {
+					t.Errorf("OutputsError path is %s, want %s", outputsErr.Path, r.OutputsPath)
+				}
+
+				if strings.Contains(err.Error(), "superSecretPassword") {
+					t.Errorf("Exec returned err containing the secret value: %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Exec returned err: %v", err)
+			}
+
+			// the secrets are only committed with the outputs file
+			// unless configured to warn on outputs errors
+			data, _ := a.ReadFile("/vela/secrets/password")
+
+			if test.failure && len(data) > 0 {
+				t.Errorf("Exec wrote secrets without the outputs file")
+			}
+
+			if !test.failure && string(data) != "superSecretPassword" {
+				t.Errorf("Exec wrote %s, want superSecretPassword", data)
+			}
+		})
+	}
+}
+
+func TestVault_Read_Exec_OutputsError_Commit(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/foo":
+			_, _ = w.Write([]byte(`{"data":{"password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		onOutputsError string
+		failure        bool
+	}{
+		{name: "fail", onOutputsError: OutputsErrorFail, failure: true},
+		{name: "warn", onOutputsError: OutputsErrorWarn, failure: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup filesystem failing to commit the outputs file
+			appFS = &failFs{Fs: newTestFS(t), path: "/vela/outputs/.env"}
+			a := &afero.Afero{Fs: appFS}
+
+			err := a.MkdirAll("/vela/outputs", 0755)
+			if err != nil {
+				t.Fatalf("MkdirAll returned err: %v", err)
+			}
+
+			r := &Read{
+				OutputsPath:    "/vela/outputs/.env",
+				OnOutputsError: test.onOutputsError,
+				Items: []*Item{
+					{
+						Source: "secret/foo",
+						Keys:   []KeyItem{{Name: "password", Path: []string{"db/password"}, Target: []string{"PASSWORD"}}},
+					},
+				},
+			}
+
+			err = r.Exec(t.Context(), v)
+
+			var outputsErr *OutputsError
+			if test.failure != errors.As(err, &outputsErr) {
+				t.Fatalf("Exec returned err %v, want %T %t", err, outputsErr, test.failure)
+			}
+
+			exists, _ := a.Exists("/vela/secrets/db/password")
+			if exists == test.failure {
+				t.Errorf("Exec wrote secrets %t, want %t", exists, !test.failure)
+			}
+
+			exists, _ = a.Exists("/vela/outputs/.env")
+			if exists {
+				t.Errorf("Exec wrote the outputs file")
+			}
+		})
+	}
+}
+
+func TestVault_Read_Exec_NoOutputsPath(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/foo":
+			_, _ = w.Write([]byte(`{"data":{"password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		onOutputsError string
+		target         []string
+		failure        bool
+	}{
+		{name: "fail", target: []string{"PASSWORD"}, failure: true},
+		{name: "warn", onOutputsError: OutputsErrorWarn, target: []string{"PASSWORD"}, failure: false},
+		{name: "no target", failure: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup filesystem
+			appFS = newTestFS(t)
+			a := &afero.Afero{Fs: appFS}
+
+			r := &Read{
+				OnOutputsError: test.onOutputsError,
+				StrictOutputs:  true,
+				Items: []*Item{
+					{
+						Source: "secret/foo",
+						Keys:   []KeyItem{{Name: "password", Path: []string{"password"}, Target: test.target}},
+					},
+				},
+			}
+
+			err := r.Exec(t.Context(), v)
+
+			if test.failure {
+				var outputsErr *OutputsError
+				if !errors.As(err, &outputsErr) || !errors.Is(err, ErrNoOutputsPath) {
+					t.Fatalf("Exec returned err %v, want %v", err, ErrNoOutputsPath)
+				}
+			} else if err != nil {
+				t.Fatalf("Exec returned err: %v", err)
+			}
+
+			data, _ := a.ReadFile("/vela/secrets/password")
+
+			if test.failure && len(data) > 0 {
+				t.Errorf("Exec wrote secrets without an outputs file")
+			}
+
+			if !test.failure && string(data) != "superSecretPassword" {
+				t.Errorf("Exec wrote %s, want superSecretPassword", data)
+			}
+		})
+	}
+}
+
+// strictOutputsDefault returns the default of the strict-outputs flag.
+func strictOutputsDefault(t *testing.T) bool {
+	t.Helper()
+
+	for _, flag := range flags() {
+		if f, ok := flag.(*cli.BoolFlag); ok && f.Name == "strict-outputs" {
+			return f.Value
+		}
+	}
+
+	t.Fatal("strict-outputs flag not found")
+
+	return false
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/afero"
+)
+
+// maxSymlinks defines the maximum number of symlinks
+// followed when resolving a path in the secrets volume.
+const maxSymlinks = 40
+
+// ErrPathTraversal defines the error type when a path
+// provided for a Vault read escapes the secrets volume.
+var ErrPathTraversal = errors.New("path escapes the secrets volume")
+
+// root returns the absolute directory of the secrets
+// volume, using the default when none was provided.
+func (r *Read) root() string {
+	root := r.SecretsRoot
+	if len(root) == 0 {
+		root = DefaultSecretsRoot
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.Clean(root)
+	}
+
+	return abs
+}
+
+// validateRoot verifies the secrets root is an existing directory.
+func (r *Read) validateRoot() error {
+	root := r.root()
+
+	info, err := appFS.Stat(root)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSecretsRoot, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrInvalidSecretsRoot, root)
+	}
+
+	return nil
+}
+
+// cleanPath returns the provided path cleaned and relative
+// to the secrets volume, returning an error for a path
+// that escapes the secrets volume (i.e. ../../etc/passwd).
+func cleanPath(pth string) (string, error) {
+	p := path.Clean(strings.Trim(pth, "/"))
+
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("%w: %s", ErrPathTraversal, pth)
+	}
+
+	return p, nil
+}
+
+// confinePath returns the location of the provided path in the
+// secrets volume at the root, verifying neither the path nor any
+// symlink in the filesystem along the path escapes the root.
+func confinePath(fs afero.Fs, root, pth string) (string, error) {
+	p, err := cleanPath(pth)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkSymlinks(fs, root, p)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(root, p), nil
+}
+
+// checkSymlinks resolves the symlinks along the path relative
+// to the root, returning an error for a symlink that resolves
+// outside of the root. Filesystems without symlinks are skipped.
+func checkSymlinks(fs afero.Fs, root, p string) error {
+	lstater, ok := fs.(afero.Lstater)
+	if !ok {
+		return nil
+	}
+
+	reader, ok := fs.(afero.LinkReader)
+	if !ok {
+		return nil
+	}
+
+	// the root of the secrets volume is trusted
+	if p == "." {
+		return nil
+	}
+
+	resolved := root
+	parts := strings.Split(p, "/")
+
+	for links := 0; len(parts) > 0; {
+		next := path.Join(resolved, parts[0])
+		parts = parts[1:]
+
+		info, _, err := lstater.LstatIfPossible(next)
+		if err != nil {
+			// nothing further along the path exists to resolve
+			if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return fmt.Errorf("%w: too many symlinks in %s", ErrPathTraversal, p)
+		}
+
+		target, err := reader.ReadlinkIfPossible(next)
+		if err != nil {
+			return err
+		}
+
+		if !path.IsAbs(target) {
+			target = path.Join(resolved, target)
+		}
+
+		target = path.Clean(target)
+
+		if target != root && !strings.HasPrefix(target, root+"/") {
+			return fmt.Errorf("%w: %s links to %s", ErrPathTraversal, next, target)
+		}
+
+		// continue resolving the remaining path from the target
+		rel := strings.TrimPrefix(strings.TrimPrefix(target, root), "/")
+		if len(rel) > 0 {
+			parts = append(strings.Split(rel, "/"), parts...)
+		}
+
+		resolved = root
+	}
+
+	return nil
+}
+
+// validateDestinations verifies every path the item writes to
+// is within the secrets volume.
+func (r *Read) validateDestinations(item *Item) error {
+	paths := append([]string{}, item.Path...)
+
+	if len(item.File) > 0 {
+		paths = append(paths, item.File)
+	}
+
+	for _, keyItem := range item.Keys {
+		paths = append(paths, keyItem.Path...)
+	}
+
+	if item.DockerAuth != nil {
+		// copy the docker auth to avoid modifying the item
+		d := *item.DockerAuth
+		d.setDefaults()
+
+		paths = append(paths, d.Path)
+	}
+
+	for _, p := range paths {
+		_, err := confinePath(appFS, r.root(), p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestVault_cleanPath(t *testing.T) {
+	// setup types
+	tests := []struct {
+		path string
+		want string
+		err  error
+	}{
+		{path: "foo", want: "foo"},
+		{path: "/foo/bar/", want: "foo/bar"},
+		{path: "foo/../bar", want: "bar"},
+		{path: "/", want: "."},
+		{path: "..", err: ErrPathTraversal},
+		{path: "../../etc/cron.d/x", err: ErrPathTraversal},
+		{path: "/foo/../../x", err: ErrPathTraversal},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := cleanPath(test.path)
+		if !errors.Is(err, test.err) {
+			t.Errorf("cleanPath(%s) returned err %v, want %v", test.path, err, test.err)
+		}
+
+		if got != test.want {
+			t.Errorf("cleanPath(%s) is %s, want %s", test.path, got, test.want)
+		}
+	}
+}
+
+func TestVault_Read_Validate_PathTraversal(t *testing.T) {
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	// setup types
+	tests := []struct {
+		name string
+		item *Item
+	}{
+		{
+			name: "path",
+			item: &Item{Source: "secret/foo", Path: []string{"../../etc"}},
+		},
+		{
+			name: "key path",
+			item: &Item{
+				Source: "secret/foo",
+				Keys:   []KeyItem{{Name: "password", Path: []string{"../../etc/cron.d/x"}}},
+			},
+		},
+		{
+			name: "file",
+			item: &Item{Source: "secret/foo", File: "/app/../../x.env", Format: FormatDotenv},
+		},
+		{
+			name: "docker auth",
+			item: &Item{Source: "secret/foo", DockerAuth: &DockerAuth{Registries: []string{"docker.company.com"}, Path: "../config.json"}},
+		},
+		{
+			name: "glob",
+			item: &Item{Source: "secret/foo/*", Path: []string{"../foo"}},
+		},
+		{
+			name: "template",
+			item: &Item{Template: "{{ .foo }}", Sources: map[string]string{"foo": "secret/foo"}, File: "../x"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Read{Items: []*Item{test.item}}
+
+			err := r.Validate()
+			if !errors.Is(err, ErrPathTraversal) {
+				t.Errorf("Validate returned err %v, want %v", err, ErrPathTraversal)
+			}
+		})
+	}
+}
+
+func TestVault_Read_SymlinkEscape(t *testing.T) {
+	// setup filesystem
+	dir := t.TempDir()
+	root := filepath.Join(dir, "secrets")
+
+	for _, d := range []string{filepath.Join(root, "inner"), filepath.Join(dir, "outside")} {
+		err := os.MkdirAll(d, 0755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %v", err)
+		}
+	}
+
+	links := map[string]string{
+		filepath.Join(root, "escape"):  filepath.Join(dir, "outside"),
+		filepath.Join(root, "chained"): "escape",
+		filepath.Join(root, "local"):   "inner",
+	}
+
+	for link, target := range links {
+		err := os.Symlink(target, link)
+		if err != nil {
+			t.Fatalf("unable to create symlink: %v", err)
+		}
+	}
+
+	appFS = afero.NewOsFs()
+
+	tests := []struct {
+		path string
+		err  error
+	}{
+		{path: "local/password", err: nil},
+		{path: "escape/password", err: ErrPathTraversal},
+		{path: "chained/nested/password", err: ErrPathTraversal},
+	}
+
+	// run tests
+	for _, test := range tests {
+		r := &Read{
+			SecretsRoot: root,
+			Items: []*Item{
+				{
+					Source: "secret/foo",
+					Keys:   []KeyItem{{Name: "password", Path: []string{test.path}}},
+				},
+			},
+		}
+
+		err := r.Validate()
+		if !errors.Is(err, test.err) {
+			t.Errorf("Validate for %s returned err %v, want %v", test.path, err, test.err)
+		}
+	}
+}
+
+func TestVault_Read_Exec_PathTraversal(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/foo":
+			_, _ = w.Write([]byte(`{"data":{"../../../etc/passwd":"superSecretValue"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+	a := &afero.Afero{Fs: appFS}
+
+	r := &Read{
+		Items: []*Item{
+			{Source: "secret/foo", Path: []string{"foo"}},
+		},
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if !errors.Is(err, ErrPathTraversal) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrPathTraversal)
+	}
+
+	exists, _ := a.Exists("/etc/passwd")
+	if exists {
+		t.Errorf("Exec wrote a key of the secret outside of the secrets volume")
+	}
+}
+
+func TestVault_Read_Exec_SecretsRoot(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abcd","lease_duration":3600,"data":{"password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		setup func(a *afero.Afero) error
+		err   error
+	}{
+		{
+			name:  "directory",
+			setup: func(a *afero.Afero) error { return a.MkdirAll("/tmp/secrets", 0755) },
+		},
+		{
+			name:  "missing",
+			setup: func(*afero.Afero) error { return nil },
+			err:   ErrInvalidSecretsRoot,
+		},
+		{
+			name:  "file",
+			setup: func(a *afero.Afero) error { return a.WriteFile("/tmp/secrets", []byte("foo"), 0600) },
+			err:   ErrInvalidSecretsRoot,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup filesystem
+			appFS = afero.NewMemMapFs()
+			a := &afero.Afero{Fs: appFS}
+
+			err := test.setup(a)
+			if err != nil {
+				t.Fatalf("unable to setup secrets root: %v", err)
+			}
+
+			r := &Read{
+				SecretsRoot: "/tmp/secrets/",
+				Items: []*Item{
+					{Source: "database/creds/readonly", Path: []string{"db"}},
+				},
+			}
+
+			err = r.Exec(t.Context(), v)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Exec returned err %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			data, err := a.ReadFile("/tmp/secrets/db/password")
+			if err != nil || string(data) != "superSecretPassword" {
+				t.Errorf("Exec wrote %s (err: %v), want superSecretPassword", data, err)
+			}
+
+			// the lease is recorded in the secrets root for the revoke
+			m, err := readManifest(a, "/tmp/secrets/"+LeaseManifest)
+			if err != nil || len(m.Leases) != 1 {
+				t.Errorf("Exec recorded manifest %v (err: %v), want 1 lease", m, err)
+			}
+
+			exists, _ := a.DirExists(DefaultSecretsRoot)
+			if exists {
+				t.Errorf("Exec wrote to the default secrets root")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -14,19 +15,21 @@ type Plugin struct {
 	Config *Config
 	// read arguments loaded for the plugin
 	Read *Read
+	// revoke arguments loaded for the plugin
+	Revoke *Revoke
 }
 
 // Exec runs the Vault plugin to read secrets into the Vela platform.
-func (p *Plugin) Exec() error {
+func (p *Plugin) Exec(ctx context.Context) error {
 	logrus.Debug("running plugin with provided configuration")
 
 	// setup connection with Vault
-	vault, err := p.Config.New()
+	vault, err := p.Config.New(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = p.Read.Exec(vault)
+	err = p.Read.Exec(ctx, vault)
 	if err != nil {
 		return err
 	}
@@ -36,6 +39,27 @@ func (p *Plugin) Exec() error {
 	return nil
 }
 
+// Cleanup runs the Vault plugin to revoke the leases and
+// tokens recorded while reading secrets into the Vela platform.
+func (p *Plugin) Cleanup(ctx context.Context) error {
+	logrus.Debug("running plugin cleanup with provided configuration")
+
+	// setup connection with Vault
+	vault, err := p.Config.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = p.Revoke.Exec(ctx, vault)
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("revoked leases and tokens")
+
+	return nil
+}
+
 // Validate verifies the plugin is properly configured.
 func (p *Plugin) Validate() error {
 	logrus.Debug("validating plugin configuration")
@@ -4,6 +4,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -11,10 +12,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/hashicorp/go-envparse"
+	"github.com/hashicorp/vault/api"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 
@@ -29,7 +32,7 @@ var (
 
 	// ErrNoPathProvided defines the error type when a
 	// no path was provided for a Vault read.
-	ErrNoPathProvided = errors.New("no `path` or `keys` provided")
+	ErrNoPathProvided = errors.New("no `path`, `keys`, `file` or `docker_auth` provided")
 
 	// ErrNoPathOrTargetProvided defines the error type when a
 	// no path or target was provided for a Vault read key item.
@@ -39,6 +42,22 @@ var (
 	// no keys was provided for a Vault read.
 	ErrNoSourceProvided = errors.New("no source provided")
 
+	// ErrInvalidMethod defines the error type when an
+	// invalid method was provided for a Vault read.
+	ErrInvalidMethod = errors.New("invalid `method` provided")
+
+	// ErrInvalidVersion defines the error type when an
+	// invalid secret version was provided for a Vault read.
+	ErrInvalidVersion = errors.New("invalid `version` provided")
+
+	// ErrInvalidFile defines the error type when an invalid
+	// file was provided for a Vault read.
+	ErrInvalidFile = errors.New("invalid `file` provided")
+
+	// ErrInvalidSecretsRoot defines the error type when the
+	// secrets root is not an existing directory.
+	ErrInvalidSecretsRoot = errors.New("invalid `secrets_root` provided")
+
 	// appFS is a new os filesystem implementation for
 	// interacting with modifications to the filesystem.
 	appFS = afero.NewOsFs()
@@ -46,15 +65,29 @@ var (
 	// regexp for environment variable key.
 	envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
-	// SecretVolumeLegacy defines volume that stores secrets during a build execution
-	// in the legacy pattern where the user defines a directory for all keys
+	// regexp for a source with a version suffix (i.e. secret/foo@3).
+	sourceVersionPattern = regexp.MustCompile(`^(.+)@([0-9]+)$`)
+)
+
+const (
+	// MethodGet defines the method used for reading a
+	// secret with a GET request (i.e. kv, database).
+	MethodGet = "get"
+
+	// MethodPost defines the method used for reading a
+	// secret with a POST request (i.e. aws/sts, pki/issue).
+	MethodPost = "post"
+
+	// DefaultSecretsRoot defines the default directory of the
+	// volume that stores secrets during a build execution.
 	//
-	//nolint: gosec // false pos
-	SecretVolumeLegacy = "/vela/secrets/%s/"
+	//nolint:gosec // false positive
+	DefaultSecretsRoot = "/vela/secrets"
 
-	// SecretVolume defines volume that stores secrets during a build execution
-	//nolint: gosec // false pos
-	SecretVolume = "/vela/secrets/%s"
+	// legacyEnvKey defines the pattern for the default environment
+	// variable of a key written with the legacy path handling,
+	// which is independent of the secrets root.
+	legacyEnvKey = "vela/secrets/%s/%s"
 )
 
 type (
@@ -68,22 +101,68 @@ type (
 		OutputsPath string
 		// outputs map
 		Outputs map[string]string
+		// leases for dynamic secrets read
+		Leases []*Lease
+		// number of items fetched from Vault at the same time
+		Parallelism int
+		// whether to only check access to the secrets without writing them
+		DryRun bool
+		// how to handle failing to write the outputs file (warn or fail)
+		OnOutputsError string
+		// whether to fail when the outputs file can not be written
+		// unless on_outputs_error is provided
+		StrictOutputs bool
+		// directory of the volume secrets are written to
+		SecretsRoot string
+
+		// results of the requests sent to Vault
+		fetcher fetcher
 	}
 
 	// Item represents how to read an item from a location and where to write it to.
 	Item struct {
 		// is the path to where the secret is stored in Vault
 		Source string
+		// is the KV version 2 version of the secret to read
+		Version int
+		// is the Vault Enterprise namespace the secret is stored in
+		Namespace string
+		// is the method used to read the secret (get or post)
+		Method string
+		// are the parameters provided when reading the secret
+		Parameters map[string]interface{}
+		// is the path to render the entire secret to in Vela
+		File string
+		// is the format to render the entire secret in
+		Format string
+		// is the inline template to render to the file
+		Template string
+		// is the workspace path of the template to render to the file
+		TemplateFile string
+		// are the paths to the secrets referenced by name in the template
+		Sources map[string]string
+		// is the docker config.json to write registry credentials to
+		DockerAuth *DockerAuth
+		// is the prefix for the environment variables of a glob source
+		TargetPrefix string
 		// are the paths to store the key in Vela
 		Path raw.StringSlice
 		// key overwrite option
-		Keys map[string]KeyItem
+		Keys []KeyItem
+		// is the mode and ownership of the files written for the item
+		FileOptions
 	}
 
 	KeyItem struct {
 		Name   string
 		Path   raw.StringSlice
 		Target raw.StringSlice
+		// format used to write the value (json, yaml or raw)
+		Format string
+		// selector used to extract a nested value (i.e. .client_email)
+		Select string
+		// mode and ownership of the files written for the key
+		FileOptions
 	}
 )
 
@@ -104,12 +183,24 @@ func (r *Read) Unmarshal() error {
 	return nil
 }
 
-// Custom unmarshal for KeyItem to translate slice to map.
+// Custom unmarshal for Item to validate key items and parse the source version.
 func (i *Item) UnmarshalJSON(data []byte) error {
 	expectedInput := new(struct {
-		Source string          `json:"source"`
-		Path   raw.StringSlice `json:"path"`
-		Keys   []KeyItem       `json:"keys"`
+		Source       string                 `json:"source"`
+		Version      int                    `json:"version"`
+		Namespace    string                 `json:"namespace"`
+		Method       string                 `json:"method"`
+		Parameters   map[string]interface{} `json:"parameters"`
+		File         string                 `json:"file"`
+		Format       string                 `json:"format"`
+		Template     string                 `json:"template"`
+		TemplateFile string                 `json:"template_file"`
+		Sources      map[string]string      `json:"sources"`
+		DockerAuth   *DockerAuth            `json:"docker_auth"`
+		TargetPrefix string                 `json:"target_prefix"`
+		Path         raw.StringSlice        `json:"path"`
+		Keys         []KeyItem              `json:"keys"`
+		FileOptions
 	})
 
 	err := json.Unmarshal(data, expectedInput)
@@ -118,36 +209,87 @@ func (i *Item) UnmarshalJSON(data []byte) error {
 	}
 
 	i.Source = expectedInput.Source
+	i.Version = expectedInput.Version
+	i.Namespace = expectedInput.Namespace
+	i.Method = strings.ToLower(expectedInput.Method)
+	i.Parameters = expectedInput.Parameters
+	i.File = expectedInput.File
+	i.Format = strings.ToLower(expectedInput.Format)
+	i.Template = expectedInput.Template
+	i.TemplateFile = expectedInput.TemplateFile
+	i.Sources = expectedInput.Sources
+	i.DockerAuth = expectedInput.DockerAuth
+	i.TargetPrefix = expectedInput.TargetPrefix
 	i.Path = expectedInput.Path
+	i.FileOptions = expectedInput.FileOptions
 
-	if len(expectedInput.Keys) > 0 {
-		i.Keys = make(map[string]KeyItem)
+	// check for the source@version shorthand
+	if match := sourceVersionPattern.FindStringSubmatch(i.Source); match != nil {
+		version, err := strconv.Atoi(match[2])
+		if err != nil {
+			return fmt.Errorf("%w for source %s: %w", ErrInvalidVersion, i.Source, err)
+		}
 
-		for _, keyItem := range expectedInput.Keys {
-			if len(keyItem.Name) == 0 {
-				return fmt.Errorf("key item missing name")
-			}
+		if i.Version > 0 && i.Version != version {
+			return fmt.Errorf("%w for source %s: conflicts with version %d", ErrInvalidVersion, i.Source, i.Version)
+		}
 
-			i.Keys[keyItem.Name] = keyItem
+		i.Source = match[1]
+		i.Version = version
+	}
+
+	// keep every key item as several can select from the same key
+	for _, keyItem := range expectedInput.Keys {
+		if len(keyItem.Name) == 0 {
+			return fmt.Errorf("key item missing name")
 		}
+
+		keyItem.Format = strings.ToLower(keyItem.Format)
+
+		i.Keys = append(i.Keys, keyItem)
 	}
 
 	return nil
 }
 
+// isWrite returns whether the item is read with a POST request,
+// as parameters can only be provided with a POST request.
+func (i *Item) isWrite() bool {
+	return i.Method == MethodPost || len(i.Parameters) > 0
+}
+
 // Exec runs the read for collecting secrets.
-func (r *Read) Exec(v *vault.Client) error {
+func (r *Read) Exec(ctx context.Context, v *vault.Client) error {
 	logrus.Debug("running plugin with provided configuration")
 
+	if r.DryRun {
+		return r.plan(ctx, v)
+	}
+
+	r.reset()
+
+	// verify the secrets root exists before writing any secrets
+	err := r.validateRoot()
+	if err != nil {
+		return err
+	}
+
+	// stage the writes for all items so nothing is written to the
+	// secrets volume or outputs file unless every item succeeds
+	s := newStage(appFS)
+
 	// use custom filesystem which enables us to test
 	a := &afero.Afero{
-		Fs: appFS,
+		Fs: s.fs,
 	}
 
 	// gather existing encoded outputs
-	rawOutputs, err := a.ReadFile(r.OutputsPath)
-	if err != nil {
-		logrus.Debug("empty masked outputs file. creating one...")
+	var rawOutputs []byte
+	if len(r.OutputsPath) > 0 {
+		rawOutputs, err = a.ReadFile(r.OutputsPath)
+		if err != nil {
+			logrus.Debug("empty masked outputs file. creating one...")
+		}
 	}
 
 	r.Outputs, err = envparse.Parse(bytes.NewReader(rawOutputs))
@@ -166,33 +308,157 @@ func (r *Read) Exec(v *vault.Client) error {
 		r.Outputs[k] = string(decodedValue)
 	}
 
+	// send the requests for all items to Vault up front
+	r.prefetch(ctx, v)
+
+	err = r.execItems(ctx, v, a)
+
+	// record the leases of every secret fetched, including
+	// items fetched ahead of a failed item that were not read
+	r.leases()
+
+	// the outputs file is committed with the secrets
+	outputs := false
+	if err == nil && len(r.Outputs) > 0 {
+		if len(r.OutputsPath) > 0 {
+			outputs, err = r.stageOutputs(s)
+		} else {
+			// the targets can neither be masked nor made available
+			err = r.outputsError(ErrNoOutputsPath)
+		}
+	}
+
+	if err == nil {
+		err = r.commit(s, outputs)
+	} else {
+		s.discard()
+	}
+
+	// the manifest does not contain any secret values and is recorded
+	// even when the read fails so generated credentials can be revoked
+	if len(r.Leases) > 0 || len(v.Accessor) > 0 {
+		mErr := r.recordManifest(&afero.Afero{Fs: appFS}, v.Accessor)
+		if mErr != nil {
+			if err != nil {
+				logrus.Warnf("unable to record leases after failure: %v", mErr)
+
+				return err
+			}
+
+			return mErr
+		}
+	}
+
+	return err
+}
+
+// execItems reads the secrets for each item and writes them as configured.
+func (r *Read) execItems(ctx context.Context, v *vault.Client, a *afero.Afero) error {
 	for _, item := range r.Items {
+		// stop reading items when the plugin is canceled
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		if item.isTemplate() {
+			// if a template is defined, render the template to the file
+			logrus.Debug("rendering template to configured file")
+
+			err := r.execTemplateItem(ctx, v, a, item)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if item.isGlob() {
+			// if the source is a glob, mirror the matching secrets to the path
+			logrus.Debug("reading secrets matching glob source")
+
+			err := r.execGlobItem(ctx, v, a, item)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// read data from the vault provider
+		secret, err := r.readItem(ctx, v, item)
+		if err != nil {
+			return err
+		}
+
 		if len(item.Keys) > 0 {
 			// if keys are defined, use new key based handling
 			logrus.Debug("iterating through configured key items")
 
-			err := r.execKeyItem(v, a, item)
+			err := r.execKeyItem(a, item, secret)
 			if err != nil {
 				return err
 			}
-		} else {
-			// if no keys defined, use legacy path based handling
+		}
+
+		if len(item.File) > 0 {
+			// if a file is defined, render the entire secret to the file
+			logrus.Debug("rendering secret to configured file")
+
+			err := r.execFileItem(a, item, secret)
+			if err != nil {
+				return err
+			}
+		}
+
+		if item.DockerAuth != nil {
+			// if docker auth is defined, write the registry credentials to the config file
+			logrus.Debug("writing registry credentials to docker config")
+
+			err := r.execDockerAuth(a, item, secret)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(item.Keys) == 0 && len(item.File) == 0 && item.DockerAuth == nil {
+			// if no keys or file defined, use legacy path based handling
 			logrus.Debug("key items not configured. using legacy path handling")
 
-			err := r.execLegacyPath(v, a, item)
+			err := r.execLegacyPath(a, item, secret)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	if len(r.Outputs) > 0 {
-		err := writeEnvFiles(a, r.Outputs, r.OutputsPath)
-		if err != nil {
-			return err
-		}
+	return nil
+}
+
+// recordManifest appends the leases for dynamic secrets read and the
+// accessor for the token created when logging in to the lease
+// manifest in the secrets volume so they can be revoked.
+func (r *Read) recordManifest(a *afero.Afero, accessor string) error {
+	path := filepath.Join(r.root(), LeaseManifest)
+
+	m, err := readManifest(a, path)
+	if err != nil {
+		return err
 	}
 
+	m.Leases = append(m.Leases, r.Leases...)
+
+	if len(accessor) > 0 {
+		m.Accessors = append(m.Accessors, accessor)
+	}
+
+	err = writeManifest(a, path, m)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("recorded %d lease(s) to %s", len(r.Leases), path)
+
 	return nil
 }
 
@@ -200,15 +466,9 @@ func (r *Read) Exec(v *vault.Client) error {
 // data keys and writing to the /vela/secrets/<path>/<key> file.
 //
 // it also populates the outputs map with the default key of VELA_SECRETS_<PATH>_<KEY>.
-func (r *Read) execLegacyPath(v *vault.Client, a *afero.Afero, item *Item) error {
-	secret, err := v.Read(item.Source)
-	if err != nil {
-		return err
-	}
-
+func (r *Read) execLegacyPath(a *afero.Afero, item *Item, secret *api.Secret) error {
 	for _, pth := range item.Path {
-		// read data from the vault provider
-		logrus.Tracef("reading data from path %s", item.Source)
+		logrus.Tracef("writing data from source %s", item.Source)
 
 		// remove any leading slashes from path
 		p := strings.TrimPrefix(pth, "/")
@@ -216,7 +476,7 @@ func (r *Read) execLegacyPath(v *vault.Client, a *afero.Afero, item *Item) error
 		// remove any trailing slashes from path
 		p = strings.TrimSuffix(p, "/")
 
-		err = r.writeLegacySecretFiles(a, p, secret.Data)
+		err := r.writeLegacySecretFiles(a, p, secret.Data, item.FileOptions)
 		if err != nil {
 			return err
 		}
@@ -225,7 +485,12 @@ func (r *Read) execLegacyPath(v *vault.Client, a *afero.Afero, item *Item) error
 			for _, v := range secret.Data {
 				envKey := sanitizeEnvKey(strings.ToUpper(strings.TrimPrefix(p, "/")))
 
-				r.Outputs[envKey] = v.(string)
+				value, err := formatValue(v, "")
+				if err != nil {
+					return err
+				}
+
+				r.Outputs[envKey] = value
 			}
 		}
 	}
@@ -235,82 +500,129 @@ func (r *Read) execLegacyPath(v *vault.Client, a *afero.Afero, item *Item) error
 
 // execKeyItem iterates over the defined keys from the `source` and writes them to their defined paths
 // or environment variables.
-func (r *Read) execKeyItem(v *vault.Client, a *afero.Afero, item *Item) error {
-	secret, err := v.Read(item.Source)
-	if err != nil {
-		return err
-	}
-
+func (r *Read) execKeyItem(a *afero.Afero, item *Item, secret *api.Secret) error {
 	for _, keyItem := range item.Keys {
-		value, ok := secret.Data[keyItem.Name]
+		data, ok := secret.Data[keyItem.Name]
 		if !ok {
 			return fmt.Errorf("key %s not found in vault secret at %s", keyItem.Name, item.Source)
 		}
 
-		for _, pth := range keyItem.Path {
-			// remove any leading slashes from path
-			p := strings.TrimPrefix(pth, "/")
-
-			// remove any trailing slashes from path
-			p = strings.TrimSuffix(p, "/")
-
-			// set the location of where to write the secret
-			path := fmt.Sprintf(SecretVolume, p)
+		var err error
 
-			// send Filesystem call to create directory path for .netrc file
-			logrus.Tracef("creating directories in path %s", p)
-
-			err := a.MkdirAll(filepath.Dir(path), 0777)
+		// extract the nested value for the key item
+		if len(keyItem.Select) > 0 {
+			data, err = selectValue(data, keyItem.Select)
 			if err != nil {
-				return err
+				return fmt.Errorf("unable to select from key %s in vault secret at %s: %w", keyItem.Name, item.Source, err)
 			}
+		}
+
+		value, err := formatValue(data, keyItem.Format)
+		if err != nil {
+			return fmt.Errorf("unable to format key %s in vault secret at %s: %w", keyItem.Name, item.Source, err)
+		}
 
-			// set the secret in the Vela temp build volume
-			logrus.Tracef("write data to file %s", p)
+		// options for the key override the options for the item
+		opts := keyItem.FileOptions.merge(item.FileOptions)
 
-			err = a.WriteFile(path, []byte(value.(string)), 0600)
+		for _, pth := range keyItem.Path {
+			err := r.writeSecretFile(a, pth, []byte(value), opts)
 			if err != nil {
 				return err
 			}
 		}
 
 		for _, target := range keyItem.Target {
-			r.Outputs[target] = value.(string)
+			r.Outputs[target] = value
 		}
 	}
 
 	return nil
 }
 
-func (r *Read) writeLegacySecretFiles(a *afero.Afero, path string, data map[string]interface{}) error {
-	// set the location of where to write the secret
-	target := fmt.Sprintf(SecretVolumeLegacy, path)
+// execFileItem renders the entire data of the `source` into
+// a single file in the configured format at the defined file path.
+func (r *Read) execFileItem(a *afero.Afero, item *Item, secret *api.Secret) error {
+	data, err := renderSecret(secret.Data, item.Format)
+	if err != nil {
+		return fmt.Errorf("unable to render vault secret at %s: %w", item.Source, err)
+	}
 
-	// send Filesystem call to create directory path for .netrc file
-	logrus.Tracef("creating directories in path %s", path)
+	return r.writeSecretFile(a, item.File, data, item.FileOptions)
+}
 
-	err := a.MkdirAll(filepath.Dir(target), 0777)
+// writeSecretFile writes the data to the provided path in the
+// secrets volume with the mode and ownership from the options.
+func (r *Read) writeSecretFile(a *afero.Afero, pth string, data []byte, opts FileOptions) error {
+	path, err := confinePath(a.Fs, r.root(), pth)
 	if err != nil {
 		return err
 	}
 
+	return writeFile(a, path, data, opts)
+}
+
+// readItem reads the secret for the item from the namespace
+// and version configured for the item, recording the lease
+// for any dynamic secret read.
+//
+// identical reads across items return the same secret.
+func (r *Read) readItem(ctx context.Context, v *vault.Client, item *Item) (*api.Secret, error) {
+	result := r.fetcher.secret(ctx, v, item)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	r.recordLease(result)
+
+	return result.secret, nil
+}
+
+// recordLease records the lease for the fetched secret
+// when it is a dynamic secret that was not yet recorded.
+func (r *Read) recordLease(result *fetch) {
+	secret := result.secret
+	if secret == nil || len(secret.LeaseID) == 0 || result.recorded {
+		return
+	}
+
+	logrus.Debugf("recording lease for secret %s", result.item.Source)
+
+	result.recorded = true
+
+	r.Leases = append(r.Leases, &Lease{
+		ID:        secret.LeaseID,
+		Duration:  secret.LeaseDuration,
+		Renewable: secret.Renewable,
+		Source:    result.item.Source,
+		Namespace: result.item.Namespace,
+	})
+}
+
+func (r *Read) writeLegacySecretFiles(a *afero.Afero, path string, data map[string]interface{}, opts FileOptions) error {
 	// loop through keys in vault secret
 	for k, v := range data {
-		path = target + k
+		// the keys of the secret are confined to the secrets volume
+		file, err := confinePath(a.Fs, r.root(), path+"/"+k)
+		if err != nil {
+			return fmt.Errorf("unable to write key %s: %w", k, err)
+		}
 
-		// set the secret in the Vela temp build volume
-		logrus.Tracef("write data to file %s", path)
+		value, err := formatValue(v, "")
+		if err != nil {
+			return fmt.Errorf("unable to format key %s: %w", k, err)
+		}
 
-		err = a.WriteFile(path, []byte(v.(string)), 0600)
+		err = writeFile(a, file, []byte(value), opts)
 		if err != nil {
 			return err
 		}
 
 		// default env key
 		if r.OutputsPath != "" {
-			envKey := sanitizeEnvKey(strings.ToUpper(strings.TrimPrefix(path, "/")))
+			envKey := sanitizeEnvKey(strings.ToUpper(fmt.Sprintf(legacyEnvKey, path, k)))
 
-			r.Outputs[envKey] = v.(string)
+			r.Outputs[envKey] = value
 		}
 	}
 
@@ -325,46 +637,207 @@ func (r *Read) Validate() error {
 		return ErrNoItemsProvided
 	}
 
+	// verify the handling of outputs errors is valid
+	switch r.OnOutputsError {
+	case "", OutputsErrorFail, OutputsErrorWarn:
+	default:
+		return fmt.Errorf("%w: %s (must be %s or %s)", ErrInvalidOnOutputsError, r.OnOutputsError, OutputsErrorWarn, OutputsErrorFail)
+	}
+
+	// verify the parallelism is not negative
+	if r.Parallelism < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidParallelism, r.Parallelism)
+	}
+
 	for i, item := range r.Items {
-		// verify that at least one path was provided
-		if len(item.Path) == 0 && len(item.Keys) == 0 {
-			return fmt.Errorf("%w for item %d %s", ErrNoPathProvided, i, r.RawItems)
+		err := r.validateItem(i, item)
+		if err != nil {
+			return err
 		}
+	}
 
-		if len(item.Keys) > 0 {
-			for k, keyItem := range item.Keys {
-				// verify that at least one path was provided for key item
-				if len(keyItem.Path) == 0 && len(keyItem.Target) == 0 {
-					return fmt.Errorf("%w for key item %s in item %d", ErrNoPathOrTargetProvided, k, i)
-				}
-			}
-		} else {
-			noPath := 0
+	return nil
+}
 
-			for _, path := range item.Path {
-				// verify that at least one non-nil path was provided
-				if len(path) != 0 {
-					noPath = 1
-					break
-				}
-			}
+// validateItem verifies the item is properly configured.
+func (r *Read) validateItem(i int, item *Item) error {
+	// verify the mode and ownership of the files are valid
+	err := item.FileOptions.Validate()
+	if err != nil {
+		return fmt.Errorf("%w for item %d", err, i)
+	}
+
+	// verify the destinations are within the secrets volume
+	err = r.validateDestinations(item)
+	if err != nil {
+		return fmt.Errorf("%w for item %d", err, i)
+	}
+
+	if item.isTemplate() {
+		return validateTemplateItem(i, item)
+	}
 
-			if noPath == 0 {
-				return fmt.Errorf("%w for item %d %s", ErrNoPathProvided, i, r.RawItems)
+	if item.isGlob() {
+		return validateGlobItem(i, item)
+	}
+
+	// verify the target prefix is only provided for a glob source
+	if len(item.TargetPrefix) > 0 {
+		return fmt.Errorf("%w for item %d: `target_prefix` requires a glob `source`", ErrInvalidGlob, i)
+	}
+
+	// verify that at least one path was provided
+	if len(item.Path) == 0 && len(item.Keys) == 0 && len(item.File) == 0 && item.DockerAuth == nil {
+		return fmt.Errorf("%w for item %d %s", ErrNoPathProvided, i, r.RawItems)
+	}
+
+	if len(item.Keys) > 0 {
+		for _, keyItem := range item.Keys {
+			err := validateKeyItem(keyItem)
+			if err != nil {
+				return fmt.Errorf("%w for key item %s in item %d", err, keyItem.Name+keyItem.Select, i)
 			}
 		}
+	} else if len(item.File) == 0 && item.DockerAuth == nil {
+		noPath := 0
+
+		for _, path := range item.Path {
+			// verify that at least one non-nil path was provided
+			if len(path) != 0 {
+				noPath = 1
+				break
+			}
+		}
+
+		if noPath == 0 {
+			return fmt.Errorf("%w for item %d %s", ErrNoPathProvided, i, r.RawItems)
+		}
+	}
+
+	// verify the format is valid for the file
+	if len(item.File) > 0 {
+		// the path would be silently ignored as the whole secret is rendered
+		if len(item.Path) > 0 {
+			return fmt.Errorf("%w for item %d: `path` not allowed with `file`", ErrInvalidFile, i)
+		}
+
+		switch item.Format {
+		case FormatDotenv, FormatJSON, FormatYAML, FormatProperties:
+		default:
+			return fmt.Errorf("%w for item %d: %s (valid formats: %s, %s, %s, %s)", ErrInvalidFormat, i, item.Format,
+				FormatDotenv, FormatJSON, FormatYAML, FormatProperties)
+		}
+	}
+
+	// verify the docker auth is valid
+	if item.DockerAuth != nil {
+		// the path would be silently ignored as only the config.json is written
+		if len(item.Path) > 0 {
+			return fmt.Errorf("%w for item %d: `path` not allowed with `docker_auth`", ErrInvalidDockerAuth, i)
+		}
+
+		err := item.DockerAuth.Validate()
+		if err != nil {
+			return fmt.Errorf("%w for item %d", err, i)
+		}
+	}
+
+	// verify source is provided
+	if len(item.Source) == 0 {
+		return fmt.Errorf("%w for item %d", ErrNoSourceProvided, i)
+	}
 
-		// verify source is provided
-		if len(item.Source) == 0 {
-			return fmt.Errorf("%w for item %d", ErrNoSourceProvided, i)
+	// verify version is not negative
+	if item.Version < 0 {
+		return fmt.Errorf("%w for item %d: %d", ErrInvalidVersion, i, item.Version)
+	}
+
+	// verify method is valid
+	switch item.Method {
+	case "", MethodPost:
+	case MethodGet:
+		if len(item.Parameters) > 0 {
+			return fmt.Errorf("%w for item %d: parameters require method %s", ErrInvalidMethod, i, MethodPost)
 		}
+	default:
+		return fmt.Errorf("%w for item %d: %s (valid methods: %s, %s)", ErrInvalidMethod, i, item.Method, MethodGet, MethodPost)
+	}
+
+	// verify version is only provided with a GET request
+	if item.Version > 0 && item.isWrite() {
+		return fmt.Errorf("%w for item %d: version requires method %s", ErrInvalidMethod, i, MethodGet)
 	}
 
 	return nil
 }
 
-// writeEnvFiles creates the k=v pairs and writes them to the correct outputs path.
-func writeEnvFiles(fs *afero.Afero, outputs map[string]string, path string) error {
+// validateTemplateItem verifies the template item is properly configured.
+func validateTemplateItem(i int, item *Item) error {
+	// verify only one template was provided
+	if len(item.Template) > 0 && len(item.TemplateFile) > 0 {
+		return fmt.Errorf("%w for item %d: only one of `template` or `template_file` allowed", ErrInvalidTemplate, i)
+	}
+
+	// verify the file to render the template to was provided
+	if len(item.File) == 0 {
+		return fmt.Errorf("%w for item %d: `file` required", ErrInvalidTemplate, i)
+	}
+
+	// verify the template is not combined with other modes
+	if len(item.Source) > 0 || len(item.Path) > 0 || len(item.Keys) > 0 || len(item.Format) > 0 {
+		return fmt.Errorf("%w for item %d: `source`, `path`, `keys` and `format` not allowed", ErrInvalidTemplate, i)
+	}
+
+	// verify at least one source was provided
+	if len(item.Sources) == 0 {
+		return fmt.Errorf("%w for item %d", ErrNoSourcesProvided, i)
+	}
+
+	for name, source := range item.Sources {
+		if len(source) == 0 {
+			return fmt.Errorf("%w for source %s in item %d", ErrNoSourceProvided, name, i)
+		}
+	}
+
+	// verify the inline template can be parsed
+	if len(item.Template) > 0 {
+		_, err := parseTemplate(nil, item)
+		if err != nil {
+			return fmt.Errorf("%w for item %d", err, i)
+		}
+	}
+
+	return nil
+}
+
+// validateKeyItem verifies the key item is properly configured.
+func validateKeyItem(keyItem KeyItem) error {
+	// verify that at least one path was provided for key item
+	if len(keyItem.Path) == 0 && len(keyItem.Target) == 0 {
+		return ErrNoPathOrTargetProvided
+	}
+
+	// verify the selector is valid for key item
+	if len(keyItem.Select) > 0 {
+		_, err := parseSelector(keyItem.Select)
+		if err != nil {
+			return err
+		}
+	}
+
+	// verify the format is valid for key item
+	switch keyItem.Format {
+	case "", FormatJSON, FormatYAML, FormatRaw:
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidFormat, keyItem.Format)
+	}
+
+	// verify the mode and ownership of the files are valid for key item
+	return keyItem.FileOptions.Validate()
+}
+
+// renderEnvFile creates the k=v pairs to write to the outputs path.
+func renderEnvFile(outputs map[string]string) ([]byte, error) {
 	buffer := new(bytes.Buffer)
 
 	keys := make([]string, 0, len(outputs))
@@ -378,7 +851,7 @@ func writeEnvFiles(fs *afero.Afero, outputs map[string]string, path string) erro
 		v := outputs[k]
 
 		if !envVarNamePattern.MatchString(k) {
-			return fmt.Errorf("invalid environment variable name: %s", k)
+			return nil, fmt.Errorf("invalid environment variable name: %s", k)
 		}
 
 		encoded := base64.StdEncoding.EncodeToString([]byte(v))
@@ -388,19 +861,7 @@ func writeEnvFiles(fs *afero.Afero, outputs map[string]string, path string) erro
 		fmt.Fprintf(buffer, "%s=%s\n", k, value)
 	}
 
-	if len(buffer.Bytes()) > 0 {
-		err := fs.WriteFile(path, buffer.Bytes(), 0600)
-		if err != nil {
-			logrus.Warn("error writing secret values to outputs file. values will not be masked if accidentally logged, nor will they be available in the environment.")
-
-			//nolint:nilerr // error string can contain sensitive information
-			return nil
-		}
-
-		logrus.Info("successfully wrote secrets to outputs file")
-	}
-
-	return nil
+	return buffer.Bytes(), nil
 }
 
 // sanitizeEnvKey is a helper function that copies the key locator logic from the godotenv library
@@ -5,6 +5,9 @@ package main
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -36,7 +39,7 @@ func TestVault_Read_Exec_Legacy(t *testing.T) {
 	}
 
 	// setup filesystem
-	appFS = afero.NewMemMapFs()
+	appFS = newTestFS(t)
 
 	// initialize vault with test data
 	//nolint: errcheck // error check not needed
@@ -46,7 +49,7 @@ func TestVault_Read_Exec_Legacy(t *testing.T) {
 		"crazy??//!.#secret": "bazzy",
 	})
 
-	err := r.Exec(vault)
+	err := r.Exec(t.Context(), vault)
 	if err != nil {
 		t.Errorf("Exec returned err: %v", err)
 	}
@@ -60,7 +63,7 @@ func TestVault_Read_Exec_Legacy(t *testing.T) {
 
 	r.OutputsPath = "/vela/outputs/masked.env"
 
-	err = r.Exec(vault)
+	err = r.Exec(t.Context(), vault)
 	if err != nil {
 		t.Errorf("Exec returned err: %v", err)
 	}
@@ -113,17 +116,17 @@ func TestVault_Read_Exec(t *testing.T) {
 		Items: []*Item{
 			{
 				Source: source,
-				Keys: map[string]KeyItem{
-					"secret": {
+				Keys: []KeyItem{
+					{
 						Name:   "secret",
 						Target: raw.StringSlice{"TEST_SECRET", "TEST_SECRET_COPY"},
 					},
-					"dash-secret": {
+					{
 						Name:   "dash-secret",
 						Target: raw.StringSlice{"TEST_DASH_SECRET"},
 						Path:   raw.StringSlice{"custom"},
 					},
-					"crazy??//!.#secret": {
+					{
 						Name:   "crazy??//!.#secret",
 						Target: raw.StringSlice{"TEST_CRAZY_SECRET"},
 					},
@@ -133,7 +136,7 @@ func TestVault_Read_Exec(t *testing.T) {
 	}
 
 	// setup filesystem
-	appFS = afero.NewMemMapFs()
+	appFS = newTestFS(t)
 
 	// initialize vault with test data
 	//nolint: errcheck // error check not needed
@@ -143,7 +146,7 @@ func TestVault_Read_Exec(t *testing.T) {
 		"crazy??//!.#secret": "bazzy",
 	})
 
-	err := r.Exec(vault)
+	err := r.Exec(t.Context(), vault)
 	if err != nil {
 		t.Errorf("Exec returned err: %v", err)
 	}
@@ -157,7 +160,7 @@ func TestVault_Read_Exec(t *testing.T) {
 
 	r.OutputsPath = "/vela/outputs/masked.env"
 
-	err = r.Exec(vault)
+	err = r.Exec(t.Context(), vault)
 	if err != nil {
 		t.Errorf("Exec returned err: %v", err)
 	}
@@ -216,7 +219,7 @@ func TestVault_Read_Exec_Fail(t *testing.T) {
 	}
 
 	// setup filesystem
-	appFS = afero.NewMemMapFs()
+	appFS = newTestFS(t)
 
 	// initialize vault with test data
 	//nolint: errcheck // error check not needed
@@ -224,7 +227,7 @@ func TestVault_Read_Exec_Fail(t *testing.T) {
 		"secret": "bar",
 	})
 
-	err := r.Exec(vault)
+	err := r.Exec(t.Context(), vault)
 	if err == nil {
 		t.Errorf("Exec should have returned err: %v", err)
 	}
@@ -236,6 +239,19 @@ func TestVault_Read_Validate_success(t *testing.T) {
 		read *Read
 		err  error
 	}{
+		{
+			// success with file
+			read: &Read{
+				Items: []*Item{
+					{
+						File:   "app.env",
+						Format: FormatDotenv,
+						Source: "/path/to/secret",
+					},
+				},
+			},
+			err: nil,
+		},
 		{
 			// success
 			read: &Read{
@@ -306,6 +322,113 @@ func TestVault_Read_Validate_failure(t *testing.T) {
 			},
 			err: ErrNoSourceProvided,
 		},
+		{
+			// error with invalid method
+			read: &Read{
+				Items: []*Item{
+					{
+						Source: "/path/to/secret",
+						Method: "delete",
+						Path:   []string{"foobar"},
+					},
+				},
+			},
+			err: ErrInvalidMethod,
+		},
+		{
+			// error with parameters and get method
+			read: &Read{
+				Items: []*Item{
+					{
+						Source:     "aws/sts/deploy",
+						Method:     MethodGet,
+						Parameters: map[string]interface{}{"ttl": "15m"},
+						Path:       []string{"foobar"},
+					},
+				},
+			},
+			err: ErrInvalidMethod,
+		},
+		{
+			// error with file and no format
+			read: &Read{
+				Items: []*Item{
+					{
+						Source: "/path/to/secret",
+						File:   "app.env",
+					},
+				},
+			},
+			err: ErrInvalidFormat,
+		},
+		{
+			// error with file and path
+			read: &Read{
+				Items: []*Item{
+					{
+						Source: "/path/to/secret",
+						File:   "app.env",
+						Format: FormatDotenv,
+						Path:   []string{"foobar"},
+					},
+				},
+			},
+			err: ErrInvalidFile,
+		},
+		{
+			// error with invalid key selector
+			read: &Read{
+				Items: []*Item{
+					{
+						Source: "/path/to/secret",
+						Keys: []KeyItem{
+							{Name: "foo", Target: raw.StringSlice{"FOO"}, Select: "client_email"},
+						},
+					},
+				},
+			},
+			err: ErrInvalidSelector,
+		},
+		{
+			// error with invalid key format
+			read: &Read{
+				Items: []*Item{
+					{
+						Source: "/path/to/secret",
+						Keys: []KeyItem{
+							{Name: "foo", Target: raw.StringSlice{"FOO"}, Format: "toml"},
+						},
+					},
+				},
+			},
+			err: ErrInvalidFormat,
+		},
+		{
+			// error with negative version
+			read: &Read{
+				Items: []*Item{
+					{
+						Source:  "/path/to/secret",
+						Version: -1,
+						Path:    []string{"foobar"},
+					},
+				},
+			},
+			err: ErrInvalidVersion,
+		},
+		{
+			// error with invalid on_outputs_error
+			read: &Read{
+				OnOutputsError: "ignore",
+				Items: []*Item{
+					{
+						Source: "/path/to/secret",
+						Path:   []string{"foobar"},
+					},
+				},
+			},
+			err: ErrInvalidOnOutputsError,
+		},
 	}
 
 	// run test
@@ -363,8 +486,8 @@ func TestVault_Read_Unmarshal(t *testing.T) {
 	want := []*Item{
 		{
 			Source: "secret/team/database",
-			Keys: map[string]KeyItem{
-				"connection": {
+			Keys: []KeyItem{
+				{
 					Name:   "connection",
 					Target: raw.StringSlice{"DB_CONNECTION"},
 				},
@@ -372,13 +495,13 @@ func TestVault_Read_Unmarshal(t *testing.T) {
 		},
 		{
 			Source: "secret/team/nua",
-			Keys: map[string]KeyItem{
-				"username": {
+			Keys: []KeyItem{
+				{
 					Name:   "username",
 					Target: raw.StringSlice{"DOCKER_USERNAME", "ARTIFACTORY_USERNAME"},
 					Path:   raw.StringSlice{"docker/username", "artifactory/username"},
 				},
-				"password": {
+				{
 					Name:   "password",
 					Target: raw.StringSlice{"DOCKER_PASSWORD", "ARTIFACTORY_PASSWORD"},
 					Path:   raw.StringSlice{"docker/password", "artifactory/password"},
@@ -452,3 +575,457 @@ func TestVault_Read_Unmarshal_Fail_BadKeyMap(t *testing.T) {
 		t.Errorf("Unmarshal should have returned err: %v", err)
 	}
 }
+
+func TestVault_Read_Unmarshal_Version(t *testing.T) {
+	// setup types
+	r := &Read{
+		RawItems: `
+		[
+			{"path":"foo","source":"secret/vela/hello_world","version":2},
+			{"path":"bar","source":"secret/vela/hello_world@3"},
+			{"path":"baz","source":"secret/vela/hello@world"}
+		]
+		`}
+
+	want := []*Item{
+		{
+			Path:    []string{"foo"},
+			Source:  "secret/vela/hello_world",
+			Version: 2,
+		},
+		{
+			Path:    []string{"bar"},
+			Source:  "secret/vela/hello_world",
+			Version: 3,
+		},
+		{
+			Path:   []string{"baz"},
+			Source: "secret/vela/hello@world",
+		},
+	}
+
+	err := r.Unmarshal()
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Items); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_Read_Unmarshal_Fail_VersionConflict(t *testing.T) {
+	// setup types
+	r := &Read{
+		RawItems: `
+		[
+			{"path":"foo","source":"secret/vela/hello_world@3","version":2}
+		]
+		`}
+
+	err := r.Unmarshal()
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Unmarshal returned err %v, want %v", err, ErrInvalidVersion)
+	}
+}
+
+func TestVault_Read_Unmarshal_Namespace(t *testing.T) {
+	// setup types
+	r := &Read{
+		RawItems: `
+		[
+			{"path":"foo","source":"secret/vela/hello_world","namespace":"team/app"}
+		]
+		`}
+
+	want := []*Item{
+		{
+			Path:      []string{"foo"},
+			Source:    "secret/vela/hello_world",
+			Namespace: "team/app",
+		},
+	}
+
+	err := r.Unmarshal()
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Items); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// newTestFS returns an in-memory filesystem
+// with the default secrets root created.
+func newTestFS(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+
+	err := fs.MkdirAll(DefaultSecretsRoot, 0777)
+	if err != nil {
+		t.Fatalf("unable to create secrets root: %v", err)
+	}
+
+	return fs
+}
+
+// newTestVault returns a Vault client backed by
+// a fake Vault server using the provided handler.
+func newTestVault(t *testing.T, handler http.HandlerFunc) *vault.Client {
+	t.Helper()
+
+	fake := httptest.NewServer(handler)
+	t.Cleanup(fake.Close)
+
+	v, err := vault.New(t.Context(), &vault.Setup{
+		Addr:       fake.URL,
+		AuthMethod: vault.TokenAuthMethod,
+		Token:      "superSecretToken",
+	})
+	if err != nil {
+		t.Fatalf("unable to create vault client: %v", err)
+	}
+
+	return v
+}
+
+func TestVault_Read_Exec_Dynamic(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abcd","lease_duration":3600,"renewable":true,"data":{"username":"v-user","password":"superSecretPassword"}}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/aws/sts/deploy":
+			_, _ = w.Write([]byte(`{"lease_id":"aws/sts/deploy/efgh","lease_duration":900,"data":{"access_key":"AKIA","secret_key":"superSecretKey"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	r := &Read{
+		Items: []*Item{
+			{
+				Source: "database/creds/readonly",
+				Keys: []KeyItem{
+					{Name: "username", Target: raw.StringSlice{"DB_USERNAME"}},
+					{Name: "password", Path: raw.StringSlice{"db/password"}},
+				},
+			},
+			{
+				Source:     "aws/sts/deploy",
+				Parameters: map[string]interface{}{"ttl": "15m"},
+				Keys: []KeyItem{
+					{Name: "access_key", Target: raw.StringSlice{"AWS_ACCESS_KEY_ID"}},
+				},
+			},
+		},
+		OutputsPath: "/vela/outputs/masked.env",
+	}
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	err := a.MkdirAll(filepath.Dir(r.OutputsPath), 0777)
+	if err != nil {
+		t.Fatalf("unable to create outputs directory: %v", err)
+	}
+
+	// run test
+	err = r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	password, err := a.ReadFile("/vela/secrets/db/password")
+	if err != nil {
+		t.Errorf("unable to read secret file: %v", err)
+	}
+
+	if string(password) != "superSecretPassword" {
+		t.Errorf("Exec is %s, want %s", password, "superSecretPassword")
+	}
+
+	if r.Outputs["DB_USERNAME"] != "v-user" {
+		t.Errorf("Exec is %v, want %v", r.Outputs["DB_USERNAME"], "v-user")
+	}
+
+	if r.Outputs["AWS_ACCESS_KEY_ID"] != "AKIA" {
+		t.Errorf("Exec is %v, want %v", r.Outputs["AWS_ACCESS_KEY_ID"], "AKIA")
+	}
+
+	got, err := readManifest(a, "/vela/secrets/.vault-leases.json")
+	if err != nil {
+		t.Errorf("unable to read lease manifest: %v", err)
+	}
+
+	want := &Manifest{
+		Leases: []*Lease{
+			{
+				ID:        "database/creds/readonly/abcd",
+				Duration:  3600,
+				Renewable: true,
+				Source:    "database/creds/readonly",
+			},
+			{
+				ID:       "aws/sts/deploy/efgh",
+				Duration: 900,
+				Source:   "aws/sts/deploy",
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Exec lease manifest mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_Read_Exec_NonString(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/app" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write([]byte(`{"data":{"port":5432,"enabled":true,"hosts":["a","b"],"creds":{"user":"octocat"}}}`))
+	})
+
+	r := &Read{
+		Items: []*Item{
+			{
+				Source: "secret/app",
+				Keys: []KeyItem{
+					{Name: "port", Target: raw.StringSlice{"PORT"}},
+					{Name: "enabled", Target: raw.StringSlice{"ENABLED"}},
+					{Name: "hosts", Target: raw.StringSlice{"HOSTS"}},
+					{Name: "creds", Path: raw.StringSlice{"creds.yaml"}, Format: FormatYAML},
+					{Name: "creds", Select: ".user", Target: raw.StringSlice{"CREDS_USER"}},
+				},
+			},
+			{
+				Source: "secret/app",
+				Path:   raw.StringSlice{"app"},
+			},
+		},
+	}
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	want := map[string]string{
+		"PORT":       "5432",
+		"ENABLED":    "true",
+		"HOSTS":      `["a","b"]`,
+		"CREDS_USER": "octocat",
+	}
+
+	if diff := cmp.Diff(want, r.Outputs); diff != "" {
+		t.Errorf("Exec outputs mismatch (-want +got):\n%s", diff)
+	}
+
+	files := map[string]string{
+		"/vela/secrets/creds.yaml":  "user: octocat\n",
+		"/vela/secrets/app/port":    "5432",
+		"/vela/secrets/app/creds":   `{"user":"octocat"}`,
+		"/vela/secrets/app/enabled": "true",
+	}
+
+	for path, want := range files {
+		got, err := a.ReadFile(path)
+		if err != nil {
+			t.Errorf("unable to read secret file %s: %v", path, err)
+
+			continue
+		}
+
+		if string(got) != want {
+			t.Errorf("Exec file %s is %q, want %q", path, got, want)
+		}
+	}
+
+	// raw format for a structured value
+	r.Items = []*Item{
+		{
+			Source: "secret/app",
+			Keys: []KeyItem{
+				{Name: "creds", Target: raw.StringSlice{"CREDS"}, Format: FormatRaw},
+			},
+		},
+	}
+
+	err = r.Exec(t.Context(), v)
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+func TestVault_Read_Unmarshal_Select(t *testing.T) {
+	// setup types
+	r := &Read{
+		RawItems: `
+		[
+			{"source":"secret/vela/gcp","keys":[
+				{"name":"creds","select":".client_email","target":"GCP_EMAIL"},
+				{"name":"creds","select":".private_key","path":"gcp/key"},
+				{"name":"creds.private_key","target":"GCP_DOTTED_KEY"},
+				{"name":"creds","select":".client_email","path":"gcp/email"}
+			]}
+		]
+		`}
+
+	want := []*Item{
+		{
+			Source: "secret/vela/gcp",
+			Keys: []KeyItem{
+				{
+					Name:   "creds",
+					Select: ".client_email",
+					Target: raw.StringSlice{"GCP_EMAIL"},
+				},
+				{
+					Name:   "creds",
+					Select: ".private_key",
+					Path:   raw.StringSlice{"gcp/key"},
+				},
+				{
+					Name:   "creds.private_key",
+					Target: raw.StringSlice{"GCP_DOTTED_KEY"},
+				},
+				{
+					Name:   "creds",
+					Select: ".client_email",
+					Path:   raw.StringSlice{"gcp/email"},
+				},
+			},
+		},
+	}
+
+	err := r.Unmarshal()
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Items); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_Read_Unmarshal_Format(t *testing.T) {
+	// setup types
+	r := &Read{
+		RawItems: `
+		[
+			{"source":"secret/vela/app","file":"app.env","format":"DOTENV"},
+			{"source":"secret/vela/gcp","keys":[{"name":"creds","format":"JSON","path":"gcp/creds"}]}
+		]
+		`}
+
+	want := []*Item{
+		{
+			Source: "secret/vela/app",
+			File:   "app.env",
+			Format: FormatDotenv,
+		},
+		{
+			Source: "secret/vela/gcp",
+			Keys: []KeyItem{
+				{
+					Name:   "creds",
+					Format: FormatJSON,
+					Path:   raw.StringSlice{"gcp/creds"},
+				},
+			},
+		},
+	}
+
+	err := r.Unmarshal()
+	if err != nil {
+		t.Fatalf("Unmarshal returned err: %v", err)
+	}
+
+	if diff := cmp.Diff(want, r.Items); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+
+	err = r.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+}
+
+func TestVault_Read_Exec_File(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/app" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write([]byte(`{"data":{"username":"octocat","password":"superSecretPassword"}}`))
+	})
+
+	r := &Read{
+		Items: []*Item{
+			{
+				Source: "secret/app",
+				File:   "compose/app.env",
+				Format: FormatDotenv,
+				Keys: []KeyItem{
+					{Name: "username", Target: raw.StringSlice{"APP_USERNAME"}},
+				},
+			},
+		},
+	}
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	got, err := a.ReadFile("/vela/secrets/compose/app.env")
+	if err != nil {
+		t.Errorf("unable to read secret file: %v", err)
+	}
+
+	want := "password='superSecretPassword'\nusername='octocat'\n"
+
+	if string(got) != want {
+		t.Errorf("Exec file is %q, want %q", got, want)
+	}
+
+	if r.Outputs["APP_USERNAME"] != "octocat" {
+		t.Errorf("Exec is %v, want %v", r.Outputs["APP_USERNAME"], "octocat")
+	}
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatDotenv defines the format for rendering a secret as a dotenv file.
+	FormatDotenv = "dotenv"
+
+	// FormatProperties defines the format for rendering a secret as a Java properties file.
+	FormatProperties = "properties"
+)
+
+// ErrInvalidDotenvKey defines the error type when a key of
+// a secret can not be rendered as a dotenv variable.
+var ErrInvalidDotenvKey = errors.New("invalid dotenv key")
+
+// renderSecret renders the entire data of a secret into
+// the provided format with deterministic key ordering.
+func renderSecret(data map[string]interface{}, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		// maps are serialized with sorted keys
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("unable to render secret as json: %w", err)
+		}
+
+		return append(out, '\n'), nil
+	case FormatYAML:
+		// maps are serialized with sorted keys
+		out, err := yaml.Marshal(normalizeValue(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to render secret as yaml: %w", err)
+		}
+
+		return out, nil
+	case FormatDotenv:
+		err := validateDotenvKeys(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return renderLines(data, func(k, v string) string {
+			return sanitizeEnvKey(k) + "=" + quoteDotenv(v)
+		})
+	case FormatProperties:
+		return renderLines(data, func(k, v string) string {
+			return escapeProperty(k, true) + "=" + escapeProperty(v, false)
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, format)
+	}
+}
+
+// renderLines renders a line for each key and value of the
+// data using the provided function, sorted by key.
+func renderLines(data map[string]interface{}, line func(k, v string) string) ([]byte, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	buffer := new(bytes.Buffer)
+
+	for _, k := range keys {
+		value, err := formatValue(data[k], "")
+		if err != nil {
+			return nil, fmt.Errorf("unable to format key %s: %w", k, err)
+		}
+
+		fmt.Fprintln(buffer, line(k, value))
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// validateDotenvKeys verifies every key of the data is rendered as
+// a valid dotenv variable that no other key is rendered as.
+func validateDotenvKeys(data map[string]interface{}) error {
+	names := make(map[string]string, len(data))
+
+	for k := range data {
+		name := sanitizeEnvKey(k)
+
+		if len(name) == 0 || unicode.IsDigit(rune(name[0])) {
+			return fmt.Errorf("%w: %s", ErrInvalidDotenvKey, k)
+		}
+
+		if other, ok := names[name]; ok {
+			keys := []string{other, k}
+			sort.Strings(keys)
+
+			return fmt.Errorf("%w: %s and %s are both rendered as %s", ErrInvalidDotenvKey, keys[0], keys[1], name)
+		}
+
+		names[name] = k
+	}
+
+	return nil
+}
+
+// quoteDotenv quotes the value for a dotenv file, using single
+// quotes for a literal value when possible.
+func quoteDotenv(v string) string {
+	if !strings.ContainsAny(v, "'\n\r") {
+		return "'" + v + "'"
+	}
+
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"$", `\$`,
+	)
+
+	return `"` + r.Replace(v) + `"`
+}
+
+// escapeProperty escapes the key or value for a Java properties file.
+func escapeProperty(v string, key bool) string {
+	buffer := new(strings.Builder)
+
+	for i, c := range v {
+		switch c {
+		case '\\':
+			buffer.WriteString(`\\`)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		case '\f':
+			buffer.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			if key || i == 0 {
+				buffer.WriteRune('\\')
+			}
+
+			buffer.WriteRune(c)
+		case ' ':
+			// escape spaces within keys and leading spaces within values
+			if key || i == 0 {
+				buffer.WriteRune('\\')
+			}
+
+			buffer.WriteRune(c)
+		default:
+			// escape non-ASCII characters as properties files are ISO-8859-1
+			if c > unicode.MaxASCII {
+				for _, u := range utf16.Encode([]rune{c}) {
+					fmt.Fprintf(buffer, `\u%04x`, u)
+				}
+
+				continue
+			}
+
+			buffer.WriteRune(c)
+		}
+	}
+
+	return buffer.String()
+}
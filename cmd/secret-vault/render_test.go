@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestVault_renderSecret(t *testing.T) {
+	// setup types
+	data := map[string]interface{}{
+		"username":   "octocat",
+		"password":   "it's a $ecret",
+		"port":       json.Number("5432"),
+		"db.url":     "jdbc:postgresql://db:5432/app",
+		"greeting":   "héllo",
+		"multi-line": "foo\nbar",
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatDotenv,
+			want: `db_url='jdbc:postgresql://db:5432/app'
+greeting='héllo'
+multi_line="foo\nbar"
+password="it's a \$ecret"
+port='5432'
+username='octocat'
+`,
+		},
+		{
+			format: FormatProperties,
+			want: `db.url=jdbc:postgresql://db:5432/app
+greeting=h\u00e9llo
+multi-line=foo\nbar
+password=it's a $ecret
+port=5432
+username=octocat
+`,
+		},
+		{
+			format: FormatJSON,
+			want: `{
+  "db.url": "jdbc:postgresql://db:5432/app",
+  "greeting": "héllo",
+  "multi-line": "foo\nbar",
+  "password": "it's a $ecret",
+  "port": 5432,
+  "username": "octocat"
+}
+`,
+		},
+		{
+			format: FormatYAML,
+			want: `db.url: jdbc:postgresql://db:5432/app
+greeting: héllo
+multi-line: |-
+    foo
+    bar
+password: it's a $ecret
+port: 5432
+username: octocat
+`,
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := renderSecret(data, test.format)
+		if err != nil {
+			t.Errorf("renderSecret for %s returned err: %v", test.format, err)
+
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("renderSecret for %s is %q, want %q", test.format, got, test.want)
+		}
+	}
+
+	_, err := renderSecret(data, "toml")
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("renderSecret returned err %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+func TestVault_renderSecret_Dotenv_Failure(t *testing.T) {
+	// setup types
+	tests := []map[string]interface{}{
+		{"db-url": "foo", "db_url": "bar"},
+		{"1password": "foo"},
+		{"": "foo"},
+	}
+
+	// run test
+	for _, test := range tests {
+		_, err := renderSecret(test, FormatDotenv)
+		if !errors.Is(err, ErrInvalidDotenvKey) {
+			t.Errorf("renderSecret for %v returned err %v, want %v", test, err, ErrInvalidDotenvKey)
+		}
+	}
+}
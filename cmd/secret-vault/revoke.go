@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/secret-vault/vault"
+)
+
+// Revoke represents the plugin configuration for revoking
+// the leases and tokens recorded in the lease manifest.
+type Revoke struct {
+	// directory of the volume the lease manifest is recorded in
+	SecretsRoot string
+}
+
+// Exec runs the revoke for the leases and tokens
+// recorded in the lease manifest.
+func (r *Revoke) Exec(ctx context.Context, v *vault.Client) error {
+	logrus.Debug("running revoke with provided configuration")
+
+	// use custom filesystem which enables us to test
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	root := r.SecretsRoot
+	if len(root) == 0 {
+		root = DefaultSecretsRoot
+	}
+
+	path := filepath.Join(root, LeaseManifest)
+
+	m, err := readManifest(a, path)
+	if err != nil {
+		return err
+	}
+
+	// capture the leases and tokens that failed to revoke
+	remaining := new(Manifest)
+
+	var errs []error
+
+	for _, lease := range m.Leases {
+		logrus.Debugf("revoking lease for secret %s", lease.Source)
+
+		client := v
+		if len(lease.Namespace) > 0 {
+			client = v.WithNamespace(lease.Namespace)
+		}
+
+		err := client.RevokeLease(ctx, lease.ID)
+		if err != nil {
+			errs = append(errs, err)
+			remaining.Leases = append(remaining.Leases, lease)
+		}
+	}
+
+	for _, accessor := range m.Accessors {
+		logrus.Debug("revoking token for recorded accessor")
+
+		err := v.RevokeAccessor(ctx, accessor)
+		if err != nil {
+			errs = append(errs, err)
+			remaining.Accessors = append(remaining.Accessors, accessor)
+		}
+	}
+
+	logrus.Infof("revoked %d lease(s) and %d token(s)",
+		len(m.Leases)-len(remaining.Leases),
+		len(m.Accessors)-len(remaining.Accessors),
+	)
+
+	// keep the leases and tokens that failed to revoke
+	// in the manifest so the revoke can be retried
+	if len(remaining.Leases) > 0 || len(remaining.Accessors) > 0 {
+		err = writeManifest(a, path, remaining)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		err = a.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	// revoke the token created when logging in for the revoke
+	if len(v.Accessor) > 0 {
+		err = v.RevokeSelf(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
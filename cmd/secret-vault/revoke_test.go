@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/secret-vault/vault"
+)
+
+func TestVault_Revoke_Exec(t *testing.T) {
+	// setup mock server
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/leases/revoke":
+			// fail to revoke leases for the restricted namespace
+			if r.Header.Get("X-Vault-Namespace") == "restricted" {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/auth/token/revoke-accessor":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer fake.Close()
+
+	v, err := vault.New(t.Context(), &vault.Setup{
+		Addr:       fake.URL,
+		AuthMethod: vault.TokenAuthMethod,
+		Token:      "superSecretToken",
+	})
+	if err != nil {
+		t.Fatalf("unable to create vault client: %v", err)
+	}
+
+	// setup filesystem
+	appFS = afero.NewMemMapFs()
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	path := "/vela/secrets/.vault-leases.json"
+
+	err = writeManifest(a, path, &Manifest{
+		Leases: []*Lease{
+			{ID: "database/creds/readonly/abcd", Source: "database/creds/readonly"},
+			{ID: "database/creds/readonly/efgh", Source: "database/creds/readonly", Namespace: "restricted"},
+		},
+		Accessors: []string{"superSecretAccessor"},
+	})
+	if err != nil {
+		t.Fatalf("unable to write lease manifest: %v", err)
+	}
+
+	// run test
+	err = new(Revoke).Exec(t.Context(), v)
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+
+	got, err := readManifest(a, path)
+	if err != nil {
+		t.Errorf("unable to read lease manifest: %v", err)
+	}
+
+	want := &Manifest{
+		Leases: []*Lease{
+			{ID: "database/creds/readonly/efgh", Source: "database/creds/readonly", Namespace: "restricted"},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Exec lease manifest mismatch (-want +got):\n%s", diff)
+	}
+
+	// revoke remaining leases once permitted
+	fake.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err = new(Revoke).Exec(t.Context(), v)
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	exists, err := a.Exists(path)
+	if err != nil || exists {
+		t.Errorf("Exec should have removed lease manifest")
+	}
+
+	// no lease manifest
+	err = new(Revoke).Exec(t.Context(), v)
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+}
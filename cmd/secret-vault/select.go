@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidSelector defines the error type when an
+	// invalid selector was provided for a Vault read key item.
+	ErrInvalidSelector = errors.New("invalid `select` provided")
+
+	// ErrSelectorNoMatch defines the error type when a
+	// selector does not match a value in the secret.
+	ErrSelectorNoMatch = errors.New("selector matched nothing")
+)
+
+// selector represents a single step of a selector,
+// either a field of an object or an index of an array.
+type selector struct {
+	// field of an object to select
+	Field string
+	// index of an array to select
+	Index int
+	// whether the step selects an array index
+	IsIndex bool
+}
+
+// parseSelector parses the provided selector (i.e. .creds.users[0]["user.name"])
+// into the steps for extracting a nested value.
+func parseSelector(s string) ([]selector, error) {
+	if !strings.HasPrefix(s, ".") && !strings.HasPrefix(s, "[") {
+		return nil, fmt.Errorf("%w: %s must start with . or [", ErrInvalidSelector, s)
+	}
+
+	steps := []selector{}
+
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			// capture the field up to the next step
+			end := strings.IndexAny(s[i+1:], ".[")
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+
+			field := s[i+1 : i+1+end]
+
+			if len(field) > 0 {
+				steps = append(steps, selector{Field: field})
+			} else if s != "." && (i+1 >= len(s) || s[i+1] != '[') {
+				// only allow an empty field for "." or ".[n]"
+				return nil, fmt.Errorf("%w: %s has an empty field", ErrInvalidSelector, s)
+			}
+
+			i += 1 + end
+		case '[':
+			end := closingBracket(s[i:])
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %s has an unclosed [", ErrInvalidSelector, s)
+			}
+
+			inner := s[i+1 : i+end]
+
+			switch {
+			case strings.HasPrefix(inner, `"`):
+				field, err := strconv.Unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s has an invalid quoted field", ErrInvalidSelector, s)
+				}
+
+				steps = append(steps, selector{Field: field})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("%w: %s has an invalid index", ErrInvalidSelector, s)
+				}
+
+				steps = append(steps, selector{Index: index, IsIndex: true})
+			}
+
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("%w: %s has an unexpected character at position %d", ErrInvalidSelector, s, i)
+		}
+	}
+
+	return steps, nil
+}
+
+// closingBracket returns the index of the ] closing the [ at the start
+// of the provided selector, skipping over a quoted field which may
+// contain a ] (i.e. ["a]b"]), or -1 when the [ is not closed.
+func closingBracket(s string) int {
+	if !strings.HasPrefix(s, `["`) {
+		return strings.Index(s, "]")
+	}
+
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// skip the escaped character
+			i++
+		case '"':
+			if i+1 < len(s) && s[i+1] == ']' {
+				return i + 1
+			}
+
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// selectValue extracts the nested value from the provided value using the selector.
+// String values are parsed as JSON before extracting the nested value.
+func selectValue(value interface{}, s string) (interface{}, error) {
+	steps, err := parseSelector(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse structured values stored as a JSON string
+	if str, ok := value.(string); ok && len(steps) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(str)))
+		decoder.UseNumber()
+
+		err := decoder.Decode(&value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s requires a JSON object or array value", ErrSelectorNoMatch, s)
+		}
+	}
+
+	for _, step := range steps {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if step.IsIndex {
+				return nil, fmt.Errorf("%w: %s indexes an object", ErrSelectorNoMatch, s)
+			}
+
+			found, ok := v[step.Field]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s field %s not found", ErrSelectorNoMatch, s, step.Field)
+			}
+
+			value = found
+		case []interface{}:
+			if !step.IsIndex {
+				return nil, fmt.Errorf("%w: %s selects field %s of an array", ErrSelectorNoMatch, s, step.Field)
+			}
+
+			if step.Index >= len(v) {
+				return nil, fmt.Errorf("%w: %s index %d out of range", ErrSelectorNoMatch, s, step.Index)
+			}
+
+			value = v[step.Index]
+		default:
+			return nil, fmt.Errorf("%w: %s selects into a %T value", ErrSelectorNoMatch, s, value)
+		}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVault_selectValue(t *testing.T) {
+	// setup types
+	value := map[string]interface{}{
+		"client_email": "octocat@example.com",
+		"users": []interface{}{
+			map[string]interface{}{"name": "octocat"},
+		},
+		"dotted.key": "dots",
+		"a]b":        "bracket",
+		`a"]b`:       "quoted bracket",
+	}
+
+	tests := []struct {
+		value    interface{}
+		selector string
+		want     interface{}
+	}{
+		{value: value, selector: ".client_email", want: "octocat@example.com"},
+		{value: value, selector: ".users[0].name", want: "octocat"},
+		{value: value, selector: `["dotted.key"]`, want: "dots"},
+		{value: value, selector: `["a]b"]`, want: "bracket"},
+		{value: value, selector: `["a\"]b"]`, want: "quoted bracket"},
+		{value: value, selector: ".", want: value},
+		{value: []interface{}{"foo", "bar"}, selector: ".[1]", want: "bar"},
+		{value: `{"client_email":"octocat@example.com","port":5432}`, selector: ".port", want: json.Number("5432")},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := selectValue(test.value, test.selector)
+		if err != nil {
+			t.Errorf("selectValue for %s returned err: %v", test.selector, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("selectValue for %s is %v, want %v", test.selector, got, test.want)
+		}
+	}
+}
+
+func TestVault_selectValue_Failure(t *testing.T) {
+	// setup types
+	value := map[string]interface{}{
+		"client_email": "octocat@example.com",
+		"users":        []interface{}{"octocat"},
+	}
+
+	tests := []struct {
+		value    interface{}
+		selector string
+		err      error
+	}{
+		{value: value, selector: ".missing", err: ErrSelectorNoMatch},
+		{value: value, selector: ".users[1]", err: ErrSelectorNoMatch},
+		{value: value, selector: ".users.name", err: ErrSelectorNoMatch},
+		{value: value, selector: ".client_email.domain", err: ErrSelectorNoMatch},
+		{value: value, selector: "[0]", err: ErrSelectorNoMatch},
+		{value: "not json", selector: ".client_email", err: ErrSelectorNoMatch},
+		{value: value, selector: "client_email", err: ErrInvalidSelector},
+		{value: value, selector: ".users[", err: ErrInvalidSelector},
+		{value: value, selector: `["a]b`, err: ErrInvalidSelector},
+		{value: value, selector: `["a"b"]`, err: ErrInvalidSelector},
+		{value: value, selector: ".users[-1]", err: ErrInvalidSelector},
+		{value: value, selector: "..users", err: ErrInvalidSelector},
+	}
+
+	// run test
+	for _, test := range tests {
+		_, err := selectValue(test.value, test.selector)
+		if !errors.Is(err, test.err) {
+			t.Errorf("selectValue for %s returned err %v, want %v", test.selector, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// stage represents the files written during a read which
+// are held in memory until every item has succeeded.
+//
+// reads through the stage see the staged files layered on top
+// of the underlying filesystem, so items that merge with an
+// existing file (i.e. docker_auth) see previously staged writes.
+type stage struct {
+	// is the filesystem the staged files are committed to
+	base afero.Fs
+	// is the in-memory filesystem holding the staged files
+	layer afero.Fs
+	// is the filesystem to read and write through while staging
+	fs *stagedFs
+}
+
+// stagedFs represents the filesystem written through while staging
+// which records the mode and ownership set for each path to apply
+// when committing, as the in-memory filesystem does not report the
+// ownership of a file.
+type stagedFs struct {
+	afero.Fs
+
+	// are the modes set for the staged paths
+	modes map[string]os.FileMode
+	// are the owners set for the staged paths
+	owners map[string][2]int
+}
+
+// newStage returns a stage for writes to the provided filesystem.
+func newStage(base afero.Fs) *stage {
+	s := &stage{base: base}
+	s.reset()
+
+	return s
+}
+
+// reset drops any staged files and recorded attributes.
+func (s *stage) reset() {
+	s.layer = afero.NewMemMapFs()
+	s.fs = &stagedFs{
+		Fs:     afero.NewCopyOnWriteFs(s.base, s.layer),
+		modes:  make(map[string]os.FileMode),
+		owners: make(map[string][2]int),
+	}
+}
+
+// LstatIfPossible returns the staged or underlying file info
+// without following a symlink when supported.
+func (f *stagedFs) LstatIfPossible(name string) (os.FileInfo, bool, error) {
+	if lstater, ok := f.Fs.(afero.Lstater); ok {
+		return lstater.LstatIfPossible(name)
+	}
+
+	info, err := f.Stat(name)
+
+	return info, false, err
+}
+
+// ReadlinkIfPossible returns the target of the symlink when supported.
+func (f *stagedFs) ReadlinkIfPossible(name string) (string, error) {
+	if reader, ok := f.Fs.(afero.LinkReader); ok {
+		return reader.ReadlinkIfPossible(name)
+	}
+
+	return "", &os.PathError{Op: "readlink", Path: name, Err: afero.ErrNoReadlink}
+}
+
+// Chmod records the mode to set on the path when committing.
+func (f *stagedFs) Chmod(name string, mode os.FileMode) error {
+	f.modes[filepath.Clean(name)] = mode.Perm()
+
+	return nil
+}
+
+// Chown records the owner to set on the path when committing.
+func (f *stagedFs) Chown(name string, uid, gid int) error {
+	f.owners[filepath.Clean(name)] = [2]int{uid, gid}
+
+	return nil
+}
+
+// entries returns the staged files and the directories
+// created for them in lexical order, so a directory is
+// always returned before its contents.
+func (s *stage) entries() ([]string, error) {
+	entries := []string{}
+
+	err := afero.Walk(s.layer, string(filepath.Separator), func(path string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != string(filepath.Separator) {
+			entries = append(entries, path)
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// files returns the number of staged files.
+func (s *stage) files() int {
+	files := 0
+
+	entries, _ := s.entries()
+	for _, path := range entries {
+		if isDir, _ := afero.IsDir(s.layer, path); !isDir {
+			files++
+		}
+	}
+
+	return files
+}
+
+// commit writes each staged file to the underlying filesystem,
+// creating the directories for the files with the recorded mode
+// and ownership.
+//
+// the commit is applied in two phases so a failure never leaves
+// some of the staged files committed. Every file is first written
+// to a temporary file in the destination directory, and only once
+// all temporary files were written are they renamed over the
+// destinations, so a file is never observed partially written or
+// missing. Any failure rolls back the commit, removing the
+// temporary files and created directories and restoring the
+// files that were replaced.
+func (s *stage) commit() error {
+	entries, err := s.entries()
+	if err != nil {
+		return fmt.Errorf("unable to list staged files: %w", err)
+	}
+
+	c := &commit{base: s.base}
+
+	err = s.prepare(c, entries)
+	if err == nil {
+		err = c.rename()
+	}
+
+	if err != nil {
+		c.rollback()
+
+		return err
+	}
+
+	c.cleanup()
+
+	logrus.Debugf("committed %d staged file(s)", len(c.files))
+
+	return nil
+}
+
+// prepare creates the directories for the staged files and
+// writes every staged file to a temporary file.
+func (s *stage) prepare(c *commit, entries []string) error {
+	for _, path := range entries {
+		info, err := s.layer.Stat(path)
+		if err != nil {
+			return &commitError{Path: path, Err: err}
+		}
+
+		if info.IsDir() {
+			err = s.commitDir(c, path, info)
+			if err != nil {
+				return &commitError{Path: path, Err: err}
+			}
+
+			continue
+		}
+
+		err = s.commitFile(c, path, info)
+		if err == nil {
+			err = c.backup(c.files[len(c.files)-1])
+		}
+
+		if err != nil {
+			return &commitError{Path: path, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// commitDir creates the staged directory in the underlying
+// filesystem when it does not already exist.
+func (s *stage) commitDir(c *commit, path string, info os.FileInfo) error {
+	exists, err := afero.DirExists(s.base, path)
+	if err != nil || exists {
+		return err
+	}
+
+	err = s.base.Mkdir(path, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	c.dirs = append(c.dirs, path)
+
+	if mode, ok := s.fs.modes[path]; ok {
+		err = s.base.Chmod(path, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	if owner, ok := s.fs.owners[path]; ok {
+		return s.base.Chown(path, owner[0], owner[1])
+	}
+
+	return nil
+}
+
+// commitFile writes the staged file to a temporary file
+// in the destination directory of the underlying filesystem.
+func (s *stage) commitFile(c *commit, path string, info os.FileInfo) error {
+	// keep the mode of an existing file to match writing the file directly
+	perm := info.Mode().Perm()
+	if existing, err := s.base.Stat(path); err == nil {
+		perm = existing.Mode().Perm()
+	}
+
+	if mode, ok := s.fs.modes[path]; ok {
+		perm = mode
+	}
+
+	data, err := afero.ReadFile(s.layer, path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := afero.TempFile(s.base, filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	// capture the temporary file to remove it on failure
+	c.files = append(c.files, &commitFile{path: path, tmp: tmp.Name()})
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+
+	if err == nil {
+		err = s.base.Chmod(tmp.Name(), perm)
+	}
+
+	// set the owner before the file is visible at the destination
+	if owner, ok := s.fs.owners[path]; ok && err == nil {
+		err = s.base.Chown(tmp.Name(), owner[0], owner[1])
+	}
+
+	return err
+}
+
+// remove drops the staged file at the path.
+func (s *stage) remove(path string) error {
+	return s.layer.Remove(path)
+}
+
+// discard drops the staged files without writing them.
+func (s *stage) discard() {
+	if files := s.files(); files > 0 {
+		logrus.Warnf("discarding %d staged file(s) after failure", files)
+	}
+
+	s.reset()
+}
+
+type (
+	// commit represents the changes made to the underlying
+	// filesystem while committing the staged files.
+	commit struct {
+		// is the filesystem the staged files are committed to
+		base afero.Fs
+		// are the directories created for the staged files
+		dirs []string
+		// are the staged files written to temporary files
+		files []*commitFile
+	}
+
+	// commitFile represents a staged file being committed.
+	commitFile struct {
+		// is the destination of the staged file
+		path string
+		// is the temporary file the staged file was written to
+		tmp string
+		// is the copy of the destination to restore on failure
+		backup string
+		// whether the temporary file was renamed to the destination
+		renamed bool
+	}
+
+	// commitError represents a failure to commit a staged
+	// file, after which the commit was rolled back.
+	commitError struct {
+		// is the path of the staged file or directory
+		Path string
+		// is the error returned when committing the path
+		Err error
+	}
+)
+
+// Error returns the error message.
+func (e *commitError) Error() string {
+	return fmt.Sprintf("unable to commit staged file %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error returned when committing the path.
+func (e *commitError) Unwrap() error {
+	return e.Err
+}
+
+// backup keeps a copy of an existing destination of the staged
+// file to restore it when the commit is rolled back.
+//
+// the copy is a hard link when the underlying filesystem is the
+// os filesystem, otherwise the contents and mode are copied.
+func (c *commit) backup(f *commitFile) error {
+	info, err := c.base.Stat(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	backup := f.tmp + ".orig"
+
+	if _, ok := c.base.(*afero.OsFs); ok {
+		err = os.Link(f.path, backup)
+	} else {
+		err = copyFile(c.base, f.path, backup, info.Mode().Perm())
+	}
+
+	if err != nil {
+		// remove any partial copy of the destination
+		_ = c.base.Remove(backup)
+
+		return err
+	}
+
+	f.backup = backup
+
+	return nil
+}
+
+// rename renames every temporary file over its destination, which
+// atomically replaces any existing file at the destination.
+func (c *commit) rename() error {
+	for _, f := range c.files {
+		err := c.base.Rename(f.tmp, f.path)
+		if err != nil {
+			return &commitError{Path: f.path, Err: err}
+		}
+
+		f.renamed = true
+	}
+
+	return nil
+}
+
+// rollback reverts the changes made to the underlying filesystem,
+// removing the temporary files, committed files and created
+// directories and restoring the files that were replaced.
+func (c *commit) rollback() {
+	for i := len(c.files) - 1; i >= 0; i-- {
+		f := c.files[i]
+
+		switch {
+		case !f.renamed:
+			// remove the temporary file to avoid leaving secrets behind
+			_ = c.base.Remove(f.tmp)
+
+			if len(f.backup) > 0 {
+				_ = c.base.Remove(f.backup)
+			}
+		case len(f.backup) > 0:
+			err := c.base.Rename(f.backup, f.path)
+			if err != nil {
+				logrus.Warnf("unable to restore %s after failure: %v", f.path, err)
+
+				_ = c.base.Remove(f.backup)
+			}
+		default:
+			_ = c.base.Remove(f.path)
+		}
+	}
+
+	// remove the directories in reverse so contents are removed first
+	for i := len(c.dirs) - 1; i >= 0; i-- {
+		_ = c.base.Remove(c.dirs[i])
+	}
+
+	logrus.Warnf("rolled back commit of %d staged file(s) after failure", len(c.files))
+}
+
+// cleanup removes the copies of the files replaced by the commit.
+func (c *commit) cleanup() {
+	for _, f := range c.files {
+		if len(f.backup) == 0 {
+			continue
+		}
+
+		err := c.base.Remove(f.backup)
+		if err != nil {
+			logrus.Warnf("unable to remove replaced file %s: %v", f.backup, err)
+		}
+	}
+}
+
+// copyFile copies the contents of the file at the source to a new
+// file at the destination with the mode.
+func copyFile(fs afero.Fs, src, dst string, mode os.FileMode) error {
+	in, err := fs.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := fs.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Close()
+	} else {
+		_ = out.Close()
+	}
+
+	if err == nil {
+		// the mode is not affected by the umask
+		err = fs.Chmod(dst, mode)
+	}
+
+	return err
+}
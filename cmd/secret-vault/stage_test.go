@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+// failFs fails renaming a file to the path to
+// inject a failure while committing staged files.
+type failFs struct {
+	afero.Fs
+
+	path string
+}
+
+func (f *failFs) Rename(oldname, newname string) error {
+	if filepath.Clean(newname) == f.path {
+		return &os.PathError{Op: "rename", Path: newname, Err: syscall.EIO}
+	}
+
+	return f.Fs.Rename(oldname, newname)
+}
+
+func TestVault_Read_Exec_Rollback(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abcd","lease_duration":3600,"data":{"password":"superSecretPassword"}}`))
+		case "/v1/secret/foo":
+			_, _ = w.Write([]byte(`{"data":{"value":"superSecretValue"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+	a := &afero.Afero{Fs: appFS}
+
+	r := &Read{
+		OutputsPath: "/vela/outputs/.env",
+		Items: []*Item{
+			{
+				Source: "database/creds/readonly",
+				Keys:   []KeyItem{{Name: "password", Path: []string{"db/password"}, Target: []string{"DB_PASSWORD"}}},
+			},
+			{Source: "secret/foo", Path: []string{"foo"}},
+			{Source: "secret/missing", Path: []string{"missing"}},
+		},
+	}
+
+	// run test
+	err := r.Exec(t.Context(), v)
+	if err == nil || !strings.Contains(err.Error(), "secret/missing") {
+		t.Fatalf("Exec returned err %v, want error for secret/missing", err)
+	}
+
+	for _, path := range []string{"/vela/secrets/db/password", "/vela/secrets/foo/value", r.OutputsPath} {
+		exists, _ := a.Exists(path)
+		if exists {
+			t.Errorf("Exec wrote %s for a failed read", path)
+		}
+	}
+
+	// the lease is recorded so the generated credentials can be revoked
+	m, err := readManifest(a, "/vela/secrets/"+LeaseManifest)
+	if err != nil {
+		t.Fatalf("readManifest returned err: %v", err)
+	}
+
+	if len(m.Leases) != 1 || m.Leases[0].ID != "database/creds/readonly/abcd" {
+		t.Errorf("Exec recorded leases %v, want database/creds/readonly/abcd", m.Leases)
+	}
+}
+
+func TestVault_Stage_Commit(t *testing.T) {
+	// setup filesystem
+	appFS = newTestFS(t)
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.WriteFile("/vela/secrets/existing", []byte("old"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile returned err: %v", err)
+	}
+
+	s := newStage(appFS)
+	staged := &afero.Afero{Fs: s.fs}
+
+	want := map[string]string{
+		"/vela/secrets/existing":    "new",
+		"/vela/secrets/foo/bar/baz": "superSecretValue",
+	}
+
+	for path, data := range want {
+		err := new(Read).writeSecretFile(staged, strings.TrimPrefix(path, "/vela/secrets/"), []byte(data), FileOptions{})
+		if err != nil {
+			t.Fatalf("writeSecretFile returned err: %v", err)
+		}
+	}
+
+	// run test
+	data, _ := a.ReadFile("/vela/secrets/existing")
+	if string(data) != "old" {
+		t.Errorf("staged write modified file before commit: %s", data)
+	}
+
+	err = s.commit()
+	if err != nil {
+		t.Fatalf("commit returned err: %v", err)
+	}
+
+	got := make(map[string]string)
+
+	err = a.Walk("/vela", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		// the mode of an existing file is kept
+		mode := os.FileMode(0600)
+		if path == "/vela/secrets/existing" {
+			mode = 0644
+		}
+
+		if info.Mode().Perm() != mode {
+			t.Errorf("commit wrote %s with mode %v, want %v", path, info.Mode().Perm(), mode)
+		}
+
+		data, err := a.ReadFile(path)
+		got[path] = string(data)
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Walk returned err: %v", err)
+	}
+
+	// no temporary files are left behind
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("commit mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_Stage_Commit_Rollback(t *testing.T) {
+	// setup filesystem failing to commit a file after others were renamed
+	appFS = &failFs{Fs: newTestFS(t), path: "/vela/secrets/c/d"}
+	a := &afero.Afero{Fs: appFS}
+
+	err := a.WriteFile("/vela/secrets/a", []byte("old"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile returned err: %v", err)
+	}
+
+	s := newStage(appFS)
+	staged := &afero.Afero{Fs: s.fs}
+
+	for _, path := range []string{"a", "b", "c/d", "e"} {
+		err := new(Read).writeSecretFile(staged, path, []byte("superSecretValue"), FileOptions{})
+		if err != nil {
+			t.Fatalf("writeSecretFile returned err: %v", err)
+		}
+	}
+
+	// run test
+	err = s.commit()
+
+	var commitErr *commitError
+	if !errors.As(err, &commitErr) || commitErr.Path != "/vela/secrets/c/d" {
+		t.Fatalf("commit returned err %v, want error for /vela/secrets/c/d", err)
+	}
+
+	got := []string{}
+
+	err = a.Walk("/vela/secrets", func(path string, _ os.FileInfo, err error) error {
+		got = append(got, path)
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Walk returned err: %v", err)
+	}
+
+	// the renamed files, temporary files and created directories are removed
+	want := []string{"/vela/secrets", "/vela/secrets/a"}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("commit mismatch (-want +got):\n%s", diff)
+	}
+
+	// the replaced file is restored
+	info, err := a.Stat("/vela/secrets/a")
+	if err != nil {
+		t.Fatalf("Stat returned err: %v", err)
+	}
+
+	data, _ := a.ReadFile("/vela/secrets/a")
+	if string(data) != "old" || info.Mode().Perm() != 0644 {
+		t.Errorf("commit left %s with mode %v, want old with mode 0644", data, info.Mode().Perm())
+	}
+}
+
+func TestVault_Stage_Commit_Rollback_OsFs(t *testing.T) {
+	// setup filesystem
+	fs := afero.NewOsFs()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+
+	err := os.WriteFile(path, []byte("old"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile returned err: %v", err)
+	}
+
+	tmp := filepath.Join(dir, ".password.tmp")
+
+	err = os.WriteFile(tmp, []byte("new"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile returned err: %v", err)
+	}
+
+	c := &commit{base: fs, files: []*commitFile{{path: path, tmp: tmp}}}
+
+	// run test
+	err = c.backup(c.files[0])
+	if err != nil {
+		t.Fatalf("backup returned err: %v", err)
+	}
+
+	// the backup is a hard link to the destination
+	info, _ := os.Stat(path)
+
+	backup, err := os.Stat(c.files[0].backup)
+	if err != nil || !os.SameFile(info, backup) {
+		t.Fatalf("backup is not a link to %s: %v", path, err)
+	}
+
+	// the destination is replaced without being removed
+	err = c.rename()
+	if err != nil {
+		t.Fatalf("rename returned err: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("rename wrote %s, want new", data)
+	}
+
+	c.rollback()
+
+	data, _ = os.ReadFile(path)
+	if string(data) != "old" {
+		t.Errorf("rollback restored %s, want old", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("rollback left %d file(s), want 1", len(entries))
+	}
+}
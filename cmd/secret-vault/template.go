@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/go-vela/secret-vault/vault"
+)
+
+var (
+	// ErrInvalidTemplate defines the error type when an
+	// invalid template was provided for a Vault read.
+	ErrInvalidTemplate = errors.New("invalid `template` provided")
+
+	// ErrNoSourcesProvided defines the error type when
+	// no sources were provided for a template item.
+	ErrNoSourcesProvided = errors.New("no `sources` provided for template item")
+
+	// templateFuncs defines the functions available within a template.
+	templateFuncs = template.FuncMap{
+		"b64enc": func(v interface{}) (string, error) {
+			s, err := formatValue(v, "")
+			if err != nil {
+				return "", err
+			}
+
+			return base64.StdEncoding.EncodeToString([]byte(s)), nil
+		},
+		"toJSON": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+
+			return string(data), nil
+		},
+	}
+)
+
+// isTemplate returns whether the item renders a template.
+func (i *Item) isTemplate() bool {
+	return len(i.Template) > 0 || len(i.TemplateFile) > 0
+}
+
+// parseTemplate parses the template for the item, reading
+// the template from the workspace when a file is provided.
+func parseTemplate(a *afero.Afero, item *Item) (*template.Template, error) {
+	text := item.Template
+
+	if len(item.TemplateFile) > 0 {
+		data, err := a.ReadFile(item.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read template file %s: %w", ErrInvalidTemplate, item.TemplateFile, err)
+		}
+
+		text = string(data)
+	}
+
+	tmpl, err := template.New(item.File).
+		Funcs(templateFuncs).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return tmpl, nil
+}
+
+// execTemplateItem reads each of the `sources` and renders the
+// template with the secret data for each source by name to the
+// defined file path.
+func (r *Read) execTemplateItem(ctx context.Context, v *vault.Client, a *afero.Afero, item *Item) error {
+	tmpl, err := parseTemplate(a, item)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(item.Sources))
+	for name := range item.Sources {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	data := make(map[string]interface{}, len(names))
+
+	for _, name := range names {
+		// read data from the vault provider for the source
+		secret, err := r.readItem(ctx, v, &Item{
+			Source:    item.Sources[name],
+			Namespace: item.Namespace,
+		})
+		if err != nil {
+			return err
+		}
+
+		data[name] = secret.Data
+	}
+
+	logrus.Tracef("rendering template to file %s", item.File)
+
+	buffer := new(bytes.Buffer)
+
+	err = tmpl.Execute(buffer, data)
+	if err != nil {
+		return fmt.Errorf("unable to render template for %s: %w", item.File, err)
+	}
+
+	return r.writeSecretFile(a, item.File, buffer.Bytes(), item.FileOptions)
+}
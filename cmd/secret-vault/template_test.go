@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestVault_Read_Exec_Template(t *testing.T) {
+	// setup types
+	v := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/secret/npm":
+			_, _ = w.Write([]byte(`{"data":{"token":"superSecretToken"}}`))
+		case "/v1/secret/artifactory":
+			_, _ = w.Write([]byte(`{"data":{"username":"octocat","password":"superSecretPassword"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// setup filesystem
+	appFS = newTestFS(t)
+
+	a := &afero.Afero{
+		Fs: appFS,
+	}
+
+	err := a.WriteFile("templates/netrc.tmpl", []byte("machine artifactory login {{ .art.username }} password {{ .art.password }}\n"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write template file: %v", err)
+	}
+
+	r := &Read{
+		Items: []*Item{
+			{
+				File:     "npm/.npmrc",
+				Template: "//registry.npmjs.org/:_authToken={{ .npm.token }}\n_auth={{ b64enc .art.username }}\n",
+				Sources: map[string]string{
+					"npm": "secret/npm",
+					"art": "secret/artifactory",
+				},
+			},
+			{
+				File:         ".netrc",
+				TemplateFile: "templates/netrc.tmpl",
+				Sources: map[string]string{
+					"art": "secret/artifactory",
+				},
+			},
+		},
+	}
+
+	err = r.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	// run test
+	err = r.Exec(t.Context(), v)
+	if err != nil {
+		t.Fatalf("Exec returned err: %v", err)
+	}
+
+	files := map[string]string{
+		"/vela/secrets/npm/.npmrc": "//registry.npmjs.org/:_authToken=superSecretToken\n_auth=b2N0b2NhdA==\n",
+		"/vela/secrets/.netrc":     "machine artifactory login octocat password superSecretPassword\n",
+	}
+
+	for path, want := range files {
+		got, err := a.ReadFile(path)
+		if err != nil {
+			t.Errorf("unable to read secret file %s: %v", path, err)
+
+			continue
+		}
+
+		if string(got) != want {
+			t.Errorf("Exec file %s is %q, want %q", path, got, want)
+		}
+	}
+
+	// missing key in template
+	r.Items = []*Item{
+		{
+			File:     ".npmrc",
+			Template: "{{ .npm.missing }}",
+			Sources:  map[string]string{"npm": "secret/npm"},
+		},
+	}
+
+	err = r.Exec(t.Context(), v)
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestVault_Read_Validate_Template(t *testing.T) {
+	// setup types
+	tests := []struct {
+		item *Item
+		err  error
+	}{
+		{ // template and template file
+			item: &Item{File: ".npmrc", Template: "{{ .npm.token }}", TemplateFile: "npmrc.tmpl", Sources: map[string]string{"npm": "secret/npm"}},
+			err:  ErrInvalidTemplate,
+		},
+		{ // no file
+			item: &Item{Template: "{{ .npm.token }}", Sources: map[string]string{"npm": "secret/npm"}},
+			err:  ErrInvalidTemplate,
+		},
+		{ // source provided
+			item: &Item{File: ".npmrc", Source: "secret/npm", Template: "{{ .npm.token }}", Sources: map[string]string{"npm": "secret/npm"}},
+			err:  ErrInvalidTemplate,
+		},
+		{ // no sources
+			item: &Item{File: ".npmrc", Template: "{{ .npm.token }}"},
+			err:  ErrNoSourcesProvided,
+		},
+		{ // empty source
+			item: &Item{File: ".npmrc", Template: "{{ .npm.token }}", Sources: map[string]string{"npm": ""}},
+			err:  ErrNoSourceProvided,
+		},
+		{ // invalid template
+			item: &Item{File: ".npmrc", Template: "{{ .npm.token ", Sources: map[string]string{"npm": "secret/npm"}},
+			err:  ErrInvalidTemplate,
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		err := (&Read{Items: []*Item{test.item}}).Validate()
+		if !errors.Is(err, test.err) {
+			t.Errorf("Validate returned err %v, want %v", err, test.err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatJSON defines the format for serializing a value as JSON.
+	FormatJSON = "json"
+
+	// FormatYAML defines the format for serializing a value as YAML.
+	FormatYAML = "yaml"
+
+	// FormatRaw defines the format for writing a scalar value as is.
+	FormatRaw = "raw"
+)
+
+// ErrInvalidFormat defines the error type when an
+// invalid format was provided for a Vault read key item.
+var ErrInvalidFormat = errors.New("invalid `format` provided")
+
+// formatValue converts the value of a secret into the string
+// written to files and outputs for the provided format.
+//
+// Without a format, scalars are rendered canonically
+// and objects and arrays are serialized as JSON.
+func formatValue(value interface{}, format string) (string, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("unable to serialize value as json: %w", err)
+		}
+
+		return string(data), nil
+	case FormatYAML:
+		data, err := yaml.Marshal(normalizeValue(value))
+		if err != nil {
+			return "", fmt.Errorf("unable to serialize value as yaml: %w", err)
+		}
+
+		return string(data), nil
+	case FormatRaw:
+		s, ok := scalarValue(value)
+		if !ok {
+			return "", fmt.Errorf("%w: unable to write %T value with format %s", ErrInvalidFormat, value, FormatRaw)
+		}
+
+		return s, nil
+	case "":
+		if s, ok := scalarValue(value); ok {
+			return s, nil
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("unable to serialize value as json: %w", err)
+		}
+
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("%w: %s (valid formats: %s)", ErrInvalidFormat, format,
+			strings.Join([]string{FormatJSON, FormatYAML, FormatRaw}, ", "))
+	}
+}
+
+// scalarValue renders the canonical string for a scalar value,
+// returning false when the value is an object or array.
+func scalarValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// normalizeValue converts any JSON numbers in the value into
+// integers or floats so they serialize as numbers in YAML.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+
+		return v.String()
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+
+		for k, val := range v {
+			m[k] = normalizeValue(val)
+		}
+
+		return m
+	case []interface{}:
+		s := make([]interface{}, 0, len(v))
+
+		for _, val := range v {
+			s = append(s, normalizeValue(val))
+		}
+
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestVault_formatValue(t *testing.T) {
+	// setup types
+	object := map[string]interface{}{
+		"client_email": "octocat@example.com",
+		"port":         json.Number("5432"),
+		"enabled":      true,
+	}
+
+	list := []interface{}{"foo", json.Number("1.5")}
+
+	tests := []struct {
+		value  interface{}
+		format string
+		want   string
+	}{
+		{value: "bar", format: "", want: "bar"},
+		{value: json.Number("42"), format: "", want: "42"},
+		{value: true, format: "", want: "true"},
+		{value: nil, format: "", want: ""},
+		{value: 3.25, format: "", want: "3.25"},
+		{value: object, format: "", want: `{"client_email":"octocat@example.com","enabled":true,"port":5432}`},
+		{value: list, format: "", want: `["foo",1.5]`},
+		{value: "bar", format: FormatRaw, want: "bar"},
+		{value: json.Number("42"), format: FormatRaw, want: "42"},
+		{value: "bar", format: FormatJSON, want: `"bar"`},
+		{value: object, format: FormatJSON, want: `{"client_email":"octocat@example.com","enabled":true,"port":5432}`},
+		{value: object, format: FormatYAML, want: "client_email: octocat@example.com\nenabled: true\nport: 5432\n"},
+		{value: list, format: FormatYAML, want: "- foo\n- 1.5\n"},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := formatValue(test.value, test.format)
+		if err != nil {
+			t.Errorf("formatValue for %v returned err: %v", test.value, err)
+
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("formatValue is %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestVault_formatValue_Failure(t *testing.T) {
+	// setup types
+	tests := []struct {
+		value  interface{}
+		format string
+	}{
+		{value: map[string]interface{}{"foo": "bar"}, format: FormatRaw},
+		{value: []interface{}{"foo"}, format: FormatRaw},
+		{value: "bar", format: "toml"},
+	}
+
+	// run test
+	for _, test := range tests {
+		_, err := formatValue(test.value, test.format)
+		if !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("formatValue returned err %v, want %v", err, ErrInvalidFormat)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// loginAppRole authenticates with the AppRole auth method
+// using the role ID and secret ID for the setup.
+func (s *Setup) loginAppRole(ctx context.Context, vault *api.Client) (*api.Secret, error) {
+	secretID := s.SecretID
+
+	// unwrap the secret ID when provided as a response wrapping token
+	if len(s.WrappedSecretID) > 0 {
+		wrapped, err := vault.Logical().UnwrapWithContext(ctx, s.WrappedSecretID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unwrap secret id: %w", err)
+		}
+
+		// the wrapping token is set on the client when unwrapping
+		vault.ClearToken()
+
+		if wrapped == nil || wrapped.Data == nil {
+			return nil, fmt.Errorf("unable to unwrap secret id: no data found")
+		}
+
+		id, ok := wrapped.Data["secret_id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("unable to unwrap secret id: no secret_id found")
+		}
+
+		secretID = id
+	}
+
+	// options for passing the role and secret IDs
+	options := map[string]interface{}{
+		"role_id":   s.RoleID,
+		"secret_id": secretID,
+	}
+
+	// the login path
+	path := s.loginPath(AppRoleLoginPath)
+
+	return vault.Logical().WriteWithContext(ctx, path, options)
+}
+
+// loginCert authenticates with the TLS certificate auth method
+// using the client certificate and role for the setup.
+func (s *Setup) loginCert(ctx context.Context, vault *api.Client) (*api.Secret, error) {
+	// options for passing the role
+	options := map[string]interface{}{}
+
+	if len(s.Role) > 0 {
+		options["name"] = s.Role
+	}
+
+	// the login path
+	path := s.loginPath(CertLoginPath)
+
+	return vault.Logical().WriteWithContext(ctx, path, options)
+}
+
+// loginJWT authenticates with the JWT auth method using
+// the token (or token file) and role for the setup.
+func (s *Setup) loginJWT(ctx context.Context, vault *api.Client) (*api.Secret, error) {
+	jwt := s.JWT
+
+	// read the token from the file when not provided directly
+	if len(jwt) == 0 && len(s.JWTFile) > 0 {
+		data, err := os.ReadFile(s.JWTFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read jwt file %s: %w", s.JWTFile, err)
+		}
+
+		jwt = strings.TrimSpace(string(data))
+	}
+
+	// options for passing the token and role
+	options := map[string]interface{}{
+		"jwt":  jwt,
+		"role": s.Role,
+	}
+
+	// the login path
+	path := s.loginPath(JWTLoginPath)
+
+	return vault.Logical().WriteWithContext(ctx, path, options)
+}
+
+// loginKubernetes authenticates with the Kubernetes auth method
+// using the service account token file and role for the setup.
+func (s *Setup) loginKubernetes(ctx context.Context, vault *api.Client) (*api.Secret, error) {
+	// default the token file to the projected service account token
+	file := s.KubernetesTokenPath
+	if len(file) == 0 {
+		file = KubernetesTokenPath
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read service account token file %s: %w", file, err)
+	}
+
+	// options for passing the service account token and role
+	options := map[string]interface{}{
+		"jwt":  strings.TrimSpace(string(data)),
+		"role": s.Role,
+	}
+
+	// the login path
+	path := s.loginPath(KubernetesLoginPath)
+
+	return vault.Logical().WriteWithContext(ctx, path, options)
+}
+
+// loginLDAP authenticates with the LDAP auth method
+// using the username and password for the setup.
+func (s *Setup) loginLDAP(ctx context.Context, vault *api.Client) (*api.Secret, error) {
+	// options for passing the password
+	options := map[string]interface{}{
+		"password": s.Password,
+	}
+
+	// the login path
+	path := fmt.Sprintf(LDAPUserPath, s.Username)
+
+	// send the login to the auth method enabled at a custom mount path
+	if mount := s.mount(); len(mount) > 0 {
+		path = fmt.Sprintf(MountUserPath, mount, s.Username)
+	}
+
+	return vault.Logical().WriteWithContext(ctx, path, options)
+}
+
+// loginPath returns the provided login path for the auth
+// method, or the login path at the custom mount path when
+// provided for the setup.
+func (s *Setup) loginPath(path string) string {
+	if mount := s.mount(); len(mount) > 0 {
+		return fmt.Sprintf(MountLoginPath, mount)
+	}
+
+	return path
+}
+
+// mount returns the custom mount path for the auth method.
+func (s *Setup) mount() string {
+	return strings.Trim(s.AuthMount, "/")
+}
@@ -0,0 +1,482 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVault_New_AppRole(t *testing.T) {
+	// setup types
+	login := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token": "superSecretClientToken",
+		},
+	}
+
+	addr := newTestServer(t, map[string]interface{}{
+		"/v1/auth/approle/login": login,
+		"/v1/sys/wrapping/unwrap": map[string]interface{}{
+			"data": map[string]interface{}{
+				"secret_id": "superSecretID",
+			},
+		},
+	})
+
+	tests := []*Setup{
+		{ // success with secret id
+			Addr:       addr,
+			AuthMethod: AppRoleAuthMethod,
+			RoleID:     "myroleid",
+			SecretID:   "superSecretID",
+		},
+		{ // success with wrapped secret id
+			Addr:            addr,
+			AuthMethod:      AppRoleAuthMethod,
+			RoleID:          "myroleid",
+			WrappedSecretID: "superSecretWrappingToken",
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := New(t.Context(), test)
+		if err != nil {
+			t.Errorf("New returned err: %v", err)
+
+			continue
+		}
+
+		if got.Vault.Token() != "superSecretClientToken" {
+			t.Errorf("New token is %s, want %s", got.Vault.Token(), "superSecretClientToken")
+		}
+	}
+}
+
+func TestVault_New_JWT(t *testing.T) {
+	// setup types
+	login := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token": "superSecretClientToken",
+		},
+	}
+
+	addr := newTestServer(t, map[string]interface{}{
+		"/v1/auth/jwt/login":  login,
+		"/v1/auth/vela/login": login,
+	})
+
+	file := filepath.Join(t.TempDir(), "token")
+
+	err := os.WriteFile(file, []byte("header.payload.signature\n"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write jwt file: %v", err)
+	}
+
+	tests := []*Setup{
+		{ // success with jwt
+			Addr:       addr,
+			AuthMethod: JWTAuthMethod,
+			JWT:        "header.payload.signature",
+			Role:       "myrole",
+		},
+		{ // success with jwt file
+			Addr:       addr,
+			AuthMethod: JWTAuthMethod,
+			JWTFile:    file,
+			Role:       "myrole",
+		},
+		{ // success with custom mount
+			Addr:       addr,
+			AuthMethod: JWTAuthMethod,
+			AuthMount:  "vela",
+			JWT:        "header.payload.signature",
+			Role:       "myrole",
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := New(t.Context(), test)
+		if err != nil {
+			t.Errorf("New returned err: %v", err)
+
+			continue
+		}
+
+		if got.Vault.Token() != "superSecretClientToken" {
+			t.Errorf("New token is %s, want %s", got.Vault.Token(), "superSecretClientToken")
+		}
+	}
+}
+
+func TestVault_New_JWT_MissingFile(t *testing.T) {
+	// setup types
+	s := &Setup{
+		Addr:       newTestServer(t, map[string]interface{}{}),
+		AuthMethod: JWTAuthMethod,
+		JWTFile:    filepath.Join(t.TempDir(), "missing"),
+		Role:       "myrole",
+	}
+
+	// run test
+	_, err := New(t.Context(), s)
+	if err == nil {
+		t.Errorf("New should have returned err")
+	}
+}
+
+func TestVault_New_Kubernetes(t *testing.T) {
+	// setup types
+	addr := newTestServer(t, map[string]interface{}{
+		"/v1/auth/kubernetes/login": map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token": "superSecretClientToken",
+			},
+		},
+	})
+
+	file := filepath.Join(t.TempDir(), "token")
+
+	err := os.WriteFile(file, []byte("header.payload.signature"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write service account token file: %v", err)
+	}
+
+	s := &Setup{
+		Addr:                addr,
+		AuthMethod:          KubernetesAuthMethod,
+		KubernetesTokenPath: file,
+		Role:                "myrole",
+	}
+
+	// run test
+	got, err := New(t.Context(), s)
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	if got.Vault.Token() != "superSecretClientToken" {
+		t.Errorf("New token is %s, want %s", got.Vault.Token(), "superSecretClientToken")
+	}
+
+	s.KubernetesTokenPath = filepath.Join(t.TempDir(), "missing")
+
+	_, err = New(t.Context(), s)
+	if err == nil {
+		t.Errorf("New should have returned err")
+	}
+}
+
+func TestVault_New_Cert(t *testing.T) {
+	// setup types
+	ca, caKey := newTestCA(t)
+	cert, key := newTestClientCert(t, ca, caKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	// setup mock server requiring a client certificate
+	fake := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/cert/login" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"superSecretClientToken"}}`))
+	}))
+	fake.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	fake.StartTLS()
+	defer fake.Close()
+
+	serverCA := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: fake.Certificate().Raw,
+	}))
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+
+	err := os.WriteFile(file, []byte(serverCA), 0600)
+	if err != nil {
+		t.Fatalf("unable to write ca file: %v", err)
+	}
+
+	tests := []*Setup{
+		{ // success with CA certificate contents
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			CACert:     serverCA,
+			ClientCert: cert,
+			ClientKey:  key,
+			Role:       "myrole",
+		},
+		{ // success with CA certificate path
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			CACert:     file,
+			ClientCert: cert,
+			ClientKey:  key,
+		},
+		{ // success with insecure
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			ClientCert: cert,
+			ClientKey:  key,
+			Insecure:   true,
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := New(t.Context(), test)
+		if err != nil {
+			t.Errorf("New returned err: %v", err)
+
+			continue
+		}
+
+		if got.Vault.Token() != "superSecretClientToken" {
+			t.Errorf("New token is %s, want %s", got.Vault.Token(), "superSecretClientToken")
+		}
+	}
+}
+
+func TestVault_New_Cert_Failure(t *testing.T) {
+	// setup types
+	ca, caKey := newTestCA(t)
+	cert, key := newTestClientCert(t, ca, caKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	// setup mock server requiring a client certificate
+	fake := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"superSecretClientToken"}}`))
+	}))
+	fake.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	fake.StartTLS()
+	defer fake.Close()
+
+	serverCA := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: fake.Certificate().Raw,
+	}))
+
+	// a valid CA that did not sign the server certificate
+	unrelated, _ := newTestCA(t)
+
+	unrelatedCA := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: unrelated.Raw,
+	}))
+
+	// a client certificate signed by a CA the server does not trust
+	otherCA, otherKey := newTestCA(t)
+	otherCert, otherCertKey := newTestClientCert(t, otherCA, otherKey)
+
+	tests := []*Setup{
+		{ // failure with unrelated CA
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			CACert:     unrelatedCA,
+			ClientCert: cert,
+			ClientKey:  key,
+		},
+		{ // failure with no client certificate
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			CACert:     serverCA,
+		},
+		{ // failure with untrusted client certificate
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			CACert:     serverCA,
+			ClientCert: otherCert,
+			ClientKey:  otherCertKey,
+		},
+		{ // failure with missing CA file
+			Addr:       fake.URL,
+			AuthMethod: CertAuthMethod,
+			CACert:     filepath.Join(t.TempDir(), "missing.pem"),
+			ClientCert: cert,
+			ClientKey:  key,
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		// avoid retrying the rejected handshakes
+		test.RetryAttempts = 1
+
+		_, err := New(t.Context(), test)
+		if err == nil {
+			t.Errorf("New should have returned err")
+		}
+	}
+}
+
+func TestVault_New_AuthMount(t *testing.T) {
+	// setup types
+	login := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token": "superSecretClientToken",
+		},
+	}
+
+	addr := newTestServer(t, map[string]interface{}{
+		"/v1/auth/ldap/login/myusername":      login,
+		"/v1/auth/corp-ldap/login/myusername": login,
+		"/v1/auth/corp-approle/login":         login,
+	})
+
+	tests := []*Setup{
+		{ // success with ldap auth method and default mount
+			Addr:       addr,
+			AuthMethod: LDAPAuthMethod,
+			Password:   "superSecretPassword",
+			Username:   "myusername",
+		},
+		{ // success with ldap auth method and custom mount
+			Addr:       addr,
+			AuthMethod: LDAPAuthMethod,
+			AuthMount:  "/corp-ldap/",
+			Password:   "superSecretPassword",
+			Username:   "myusername",
+		},
+		{ // success with approle auth method and custom mount
+			Addr:       addr,
+			AuthMethod: AppRoleAuthMethod,
+			AuthMount:  "corp-approle",
+			RoleID:     "myroleid",
+			SecretID:   "superSecretID",
+		},
+	}
+
+	// run test
+	for _, test := range tests {
+		got, err := New(t.Context(), test)
+		if err != nil {
+			t.Errorf("New returned err: %v", err)
+
+			continue
+		}
+
+		if got.Vault.Token() != "superSecretClientToken" {
+			t.Errorf("New token is %s, want %s", got.Vault.Token(), "superSecretClientToken")
+		}
+	}
+
+	// failure with ldap auth method and unknown mount
+	_, err := New(t.Context(), &Setup{
+		Addr:       addr,
+		AuthMethod: LDAPAuthMethod,
+		AuthMount:  "missing",
+		Password:   "superSecretPassword",
+		Username:   "myusername",
+	})
+	if err == nil {
+		t.Errorf("New should have returned err")
+	}
+}
+
+// newTestCA returns a self-signed CA certificate and its key.
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate ca key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "secret-vault test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create ca certificate: %v", err)
+	}
+
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse ca certificate: %v", err)
+	}
+
+	return ca, key
+}
+
+// newTestClientCert writes a client certificate signed by the
+// CA and its key, returning the paths to the written files.
+func newTestClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate client key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "secret-vault test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("unable to create client certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal client key: %v", err)
+	}
+
+	dir := t.TempDir()
+	cert := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+
+	err = os.WriteFile(cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatalf("unable to write client certificate: %v", err)
+	}
+
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		t.Fatalf("unable to write client key: %v", err)
+	}
+
+	return cert, keyFile
+}
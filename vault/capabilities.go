@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Capabilities is a function to capture the capabilities
+// of the token set in the client for the provided path.
+func (c *Client) Capabilities(ctx context.Context, path string) ([]string, error) {
+	// remove any leading slashes from path
+	p := strings.TrimPrefix(path, "/")
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to capture the capabilities
+	capabilities, err := c.Vault.Sys().CapabilitiesSelfWithContext(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve capabilities for %s: %w", path, err)
+	}
+
+	return capabilities, nil
+}
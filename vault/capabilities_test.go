@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVault_Capabilities(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/capabilities-self": map[string]interface{}{
+			"data": map[string]interface{}{
+				"capabilities":   []string{"update"},
+				"aws/sts/deploy": []string{"update"},
+			},
+		},
+	})
+
+	want := []string{"update"}
+
+	// run test
+	got, err := vault.Capabilities(t.Context(), "/aws/sts/deploy")
+	if err != nil {
+		t.Fatalf("Capabilities returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Capabilities is %v, want %v", got, want)
+	}
+}
@@ -3,6 +3,8 @@
 package vault
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v3"
 )
 
@@ -29,14 +31,113 @@ var Flags = []cli.Flag{
 	},
 	&cli.StringFlag{
 		Name:    "config.auth-method",
-		Usage:   "authentication method for interfacing instance - options: (token|ldap)",
+		Usage:   "authentication method for interfacing instance - options: (token|ldap|approle|jwt|kubernetes|cert)",
 		Sources: cli.EnvVars("PARAMETER_AUTH_METHOD", "SECRET_AUTH_METHOD", "VAULT_AUTH_METHOD"),
 	},
+	&cli.StringFlag{
+		Name:    "config.auth-mount",
+		Usage:   "mount path of the auth method for interfacing instance - defaults to the auth method",
+		Sources: cli.EnvVars("PARAMETER_AUTH_MOUNT", "SECRET_VAULT_AUTH_MOUNT", "VELA_VAULT_AUTH_MOUNT", "VAULT_AUTH_MOUNT"),
+	},
+	&cli.StringFlag{
+		Name:    "config.ca-cert",
+		Usage:   "PEM-encoded CA certificate (or path to it) to verify the instance",
+		Sources: cli.EnvVars("PARAMETER_CA_CERT", "SECRET_VAULT_CA_CERT", "VELA_VAULT_CA_CERT", "VAULT_CACERT"),
+	},
+	&cli.StringFlag{
+		Name:    "config.ca-path",
+		Usage:   "path to a directory of PEM-encoded CA certificates to verify the instance",
+		Sources: cli.EnvVars("PARAMETER_CA_PATH", "SECRET_VAULT_CA_PATH", "VELA_VAULT_CA_PATH", "VAULT_CAPATH"),
+	},
+	&cli.StringFlag{
+		Name:    "config.client-cert",
+		Usage:   "path to the client certificate for communicating with the instance",
+		Sources: cli.EnvVars("PARAMETER_CLIENT_CERT", "SECRET_VAULT_CLIENT_CERT", "VELA_VAULT_CLIENT_CERT", "VAULT_CLIENT_CERT"),
+	},
+	&cli.StringFlag{
+		Name:    "config.client-key",
+		Usage:   "path to the client key for communicating with the instance",
+		Sources: cli.EnvVars("PARAMETER_CLIENT_KEY", "SECRET_VAULT_CLIENT_KEY", "VELA_VAULT_CLIENT_KEY", "VAULT_CLIENT_KEY"),
+	},
+	&cli.BoolFlag{
+		Name:    "config.insecure",
+		Usage:   "skip verification of the instance certificate",
+		Sources: cli.EnvVars("PARAMETER_INSECURE", "SECRET_VAULT_INSECURE", "VELA_VAULT_INSECURE", "VAULT_SKIP_VERIFY"),
+	},
+	&cli.StringFlag{
+		Name:    "config.jwt",
+		Usage:   "token for server authentication with JWT",
+		Sources: cli.EnvVars("PARAMETER_JWT", "SECRET_VAULT_JWT", "VELA_VAULT_JWT", "VAULT_JWT"),
+	},
+	&cli.StringFlag{
+		Name:    "config.jwt-file",
+		Usage:   "file containing the token for server authentication with JWT",
+		Sources: cli.EnvVars("PARAMETER_JWT_FILE", "SECRET_VAULT_JWT_FILE", "VELA_VAULT_JWT_FILE", "VAULT_JWT_FILE"),
+	},
+	&cli.StringFlag{
+		Name:    "config.kubernetes-token-path",
+		Usage:   "file containing the service account token for server authentication with Kubernetes",
+		Sources: cli.EnvVars("PARAMETER_KUBERNETES_TOKEN_PATH", "SECRET_VAULT_KUBERNETES_TOKEN_PATH", "VELA_VAULT_KUBERNETES_TOKEN_PATH", "VAULT_KUBERNETES_TOKEN_PATH"),
+	},
+	&cli.StringFlag{
+		Name:    "config.namespace",
+		Usage:   "Vault Enterprise namespace for the instance",
+		Sources: cli.EnvVars("PARAMETER_NAMESPACE", "SECRET_VAULT_NAMESPACE", "VELA_VAULT_NAMESPACE", "VAULT_NAMESPACE"),
+	},
 	&cli.StringFlag{
 		Name:    "config.password",
 		Usage:   "password for server authentication with LDAP",
 		Sources: cli.EnvVars("PARAMETER_PASSWORD", "SECRET_VAULT_PASSWORD", "VELA_VAULT_PASSWORD", "VAULT_PASSWORD"),
 	},
+	&cli.DurationFlag{
+		Name:    "config.request-timeout",
+		Value:   60 * time.Second,
+		Usage:   "timeout for each attempt of a request to the instance",
+		Sources: cli.EnvVars("PARAMETER_REQUEST_TIMEOUT", "SECRET_VAULT_REQUEST_TIMEOUT", "VELA_VAULT_REQUEST_TIMEOUT", "VAULT_CLIENT_TIMEOUT"),
+	},
+	&cli.IntFlag{
+		Name:    "config.retry-attempts",
+		Value:   3,
+		Usage:   "maximum number of attempts of a request to the instance",
+		Sources: cli.EnvVars("PARAMETER_RETRY_ATTEMPTS", "SECRET_VAULT_RETRY_ATTEMPTS", "VELA_VAULT_RETRY_ATTEMPTS", "VAULT_RETRY_ATTEMPTS"),
+	},
+	&cli.DurationFlag{
+		Name:    "config.retry-backoff",
+		Value:   time.Second,
+		Usage:   "initial time to wait between attempts of a request to the instance - doubled for each attempt",
+		Sources: cli.EnvVars("PARAMETER_RETRY_BACKOFF", "SECRET_VAULT_RETRY_BACKOFF", "VELA_VAULT_RETRY_BACKOFF", "VAULT_RETRY_BACKOFF"),
+	},
+	&cli.FloatFlag{
+		Name:    "config.retry-jitter",
+		Value:   0.2,
+		Usage:   "fraction of the time to wait between attempts of a request to randomly add - between 0 and 1",
+		Sources: cli.EnvVars("PARAMETER_RETRY_JITTER", "SECRET_VAULT_RETRY_JITTER", "VELA_VAULT_RETRY_JITTER", "VAULT_RETRY_JITTER"),
+	},
+	&cli.StringFlag{
+		Name:    "config.role",
+		Usage:   "role for server authentication with JWT, Kubernetes or cert",
+		Sources: cli.EnvVars("PARAMETER_ROLE", "SECRET_VAULT_ROLE", "VELA_VAULT_ROLE", "VAULT_ROLE"),
+	},
+	&cli.StringFlag{
+		Name:    "config.role-id",
+		Usage:   "role ID for server authentication with AppRole",
+		Sources: cli.EnvVars("PARAMETER_ROLE_ID", "SECRET_VAULT_ROLE_ID", "VELA_VAULT_ROLE_ID", "VAULT_ROLE_ID"),
+	},
+	&cli.StringFlag{
+		Name:    "config.secret-id",
+		Usage:   "secret ID for server authentication with AppRole",
+		Sources: cli.EnvVars("PARAMETER_SECRET_ID", "SECRET_VAULT_SECRET_ID", "VELA_VAULT_SECRET_ID", "VAULT_SECRET_ID"),
+	},
+	&cli.DurationFlag{
+		Name:    "config.timeout",
+		Usage:   "overall deadline for logging in and reading from the instance - disabled when 0",
+		Sources: cli.EnvVars("PARAMETER_TIMEOUT", "SECRET_VAULT_TIMEOUT", "VELA_VAULT_TIMEOUT", "VAULT_TIMEOUT"),
+	},
+	&cli.StringFlag{
+		Name:    "config.tls-server-name",
+		Usage:   "server name used for SNI when connecting to the instance",
+		Sources: cli.EnvVars("PARAMETER_TLS_SERVER_NAME", "SECRET_VAULT_TLS_SERVER_NAME", "VELA_VAULT_TLS_SERVER_NAME", "VAULT_TLS_SERVER_NAME"),
+	},
 	&cli.StringFlag{
 		Name:    "config.token",
 		Usage:   "token for server authentication",
@@ -47,4 +148,9 @@ var Flags = []cli.Flag{
 		Usage:   "username for server authentication with LDAP",
 		Sources: cli.EnvVars("PARAMETER_USERNAME", "SECRET_VAULT_USERNAME", "VELA_VAULT_USERNAME", "VAULT_USERNAME"),
 	},
+	&cli.StringFlag{
+		Name:    "config.wrapped-secret-id",
+		Usage:   "response wrapping token containing the secret ID for server authentication with AppRole",
+		Sources: cli.EnvVars("PARAMETER_WRAPPED_SECRET_ID", "SECRET_VAULT_WRAPPED_SECRET_ID", "VELA_VAULT_WRAPPED_SECRET_ID", "VAULT_WRAPPED_SECRET_ID"),
+	},
 }
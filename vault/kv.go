@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// KVVersion1 represents a version 1 key/value secrets engine mount.
+	KVVersion1 = 1
+
+	// KVVersion2 represents a version 2 key/value secrets engine mount.
+	KVVersion2 = 2
+
+	// mountsPath defines the path used to look up
+	// the mount information for a provided path.
+	mountsPath = "sys/internal/ui/mounts/%s"
+)
+
+// mount represents the information captured for a
+// secrets engine mount used when building a read path.
+type mount struct {
+	// path the secrets engine is mounted at
+	Path string
+	// type of the secrets engine (i.e. kv or database)
+	Type string
+	// version of the key/value secrets engine
+	Version int
+}
+
+// IsKV returns whether the provided path is within a key/value
+// secrets engine mount, where reading a secret never generates
+// credentials. A path with unknown mount information is not
+// reported as a key/value secrets engine.
+func (c *Client) IsKV(ctx context.Context, path string) bool {
+	m := c.mount(ctx, strings.TrimPrefix(path, "/"))
+
+	switch m.Type {
+	case "kv", "generic":
+		return true
+	default:
+		return false
+	}
+}
+
+// mount captures the secrets engine mount information
+// for the provided path, caching the result per mount.
+//
+// the lookup is sent without holding the lock so paths
+// can be looked up at the same time.
+func (c *Client) mount(ctx context.Context, p string) *mount {
+	// check for a cached mount that contains the provided path
+	if m := c.cachedMount(p); m != nil {
+		return m
+	}
+
+	// default to a version 1 mount to match the historical behavior
+	m := &mount{Version: KVVersion1}
+
+	logrus.Tracef("looking up mount information for path %s", p)
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to capture the mount information
+	secret, err := c.Vault.Logical().ReadWithContext(ctx, fmt.Sprintf(mountsPath, p))
+	if err != nil || secret == nil || secret.Data == nil {
+		logrus.Debugf("unable to capture mount information for path %s, assuming kv version 1", p)
+
+		return m
+	}
+
+	mountPath, ok := secret.Data["path"].(string)
+	if !ok || len(mountPath) == 0 {
+		return m
+	}
+
+	m.Path = mountPath
+	m.Type, _ = secret.Data["type"].(string)
+
+	if options, ok := secret.Data["options"].(map[string]interface{}); ok {
+		if version, ok := options["version"].(string); ok && version == "2" {
+			m.Version = KVVersion2
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mounts == nil {
+		c.mounts = make(map[string]*mount)
+	}
+
+	c.mounts[mountPath] = m
+
+	return m
+}
+
+// cachedMount returns the cached mount information
+// containing the provided path if it exists.
+func (c *Client) cachedMount(p string) *mount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for prefix, m := range c.mounts {
+		if strings.HasPrefix(p, prefix) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// dataPath returns the path for reading the data of a
+// secret at the provided path within a KV version 2 mount.
+func (m *mount) dataPath(p string) string {
+	return m.apiPath(p, "data")
+}
+
+// apiPath returns the path for the provided KV version 2
+// API prefix (i.e. data or metadata) for the provided path.
+func (m *mount) apiPath(p, prefix string) string {
+	// the path already includes the API prefix
+	if strings.HasPrefix(p, path.Join(m.Path, prefix)+"/") {
+		return p
+	}
+
+	// the trailing slash allows the root of the mount to be provided
+	return path.Join(m.Path, prefix, strings.TrimPrefix(p+"/", m.Path))
+}
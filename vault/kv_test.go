@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVault_Read_KVVersion2(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/internal/ui/mounts/secret/foo": map[string]interface{}{
+			"data": map[string]interface{}{
+				"path":    "secret/",
+				"type":    "kv",
+				"options": map[string]interface{}{"version": "2"},
+			},
+		},
+		"/v1/secret/data/foo": map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"secret": "bar"},
+				"metadata": map[string]interface{}{"version": 1},
+			},
+		},
+	})
+
+	want := map[string]interface{}{
+		"secret": "bar",
+	}
+
+	// run test
+	for _, path := range []string{"secret/foo", "/secret/foo", "secret/data/foo"} {
+		got, err := vault.Read(t.Context(), path)
+		if err != nil {
+			t.Errorf("Read for %s returned err: %v", path, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got.Data, want) {
+			t.Errorf("Read for %s is %+v, want %+v", path, got.Data, want)
+		}
+	}
+}
+
+func TestVault_Read_KVVersion1(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/internal/ui/mounts/secret/foo": map[string]interface{}{
+			"data": map[string]interface{}{
+				"path":    "secret/",
+				"type":    "kv",
+				"options": map[string]interface{}{"version": "1"},
+			},
+		},
+		"/v1/secret/foo": map[string]interface{}{
+			"data": map[string]interface{}{"secret": "bar"},
+		},
+	})
+
+	want := map[string]interface{}{
+		"secret": "bar",
+	}
+
+	// run test
+	got, err := vault.Read(t.Context(), "secret/foo")
+	if err != nil {
+		t.Errorf("Read returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("Read is %+v, want %+v", got.Data, want)
+	}
+}
+
+func TestVault_IsKV(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/internal/ui/mounts/secret/foo": map[string]interface{}{
+			"data": map[string]interface{}{
+				"path":    "secret/",
+				"type":    "kv",
+				"options": map[string]interface{}{"version": "2"},
+			},
+		},
+		"/v1/sys/internal/ui/mounts/database/creds/readonly": map[string]interface{}{
+			"data": map[string]interface{}{
+				"path": "database/",
+				"type": "database",
+			},
+		},
+	})
+
+	tests := map[string]bool{
+		"/secret/foo":             true,
+		"database/creds/readonly": false,
+		"unknown/foo":             false,
+	}
+
+	// run test
+	for path, want := range tests {
+		got := vault.IsKV(t.Context(), path)
+		if got != want {
+			t.Errorf("IsKV for %s is %t, want %t", path, got, want)
+		}
+	}
+}
+
+func TestVault_Read_NoMountInformation(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/secret/foo": map[string]interface{}{
+			"data": map[string]interface{}{"secret": "bar"},
+		},
+	})
+
+	want := map[string]interface{}{
+		"secret": "bar",
+	}
+
+	// run test
+	got, err := vault.Read(t.Context(), "secret/foo")
+	if err != nil {
+		t.Errorf("Read returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("Read is %+v, want %+v", got.Data, want)
+	}
+}
+
+func TestVault_Mount_DataPath(t *testing.T) {
+	// setup types
+	m := &mount{Path: "secret/", Version: KVVersion2}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "secret/foo", want: "secret/data/foo"},
+		{path: "secret/team/app/foo", want: "secret/data/team/app/foo"},
+		{path: "secret/data/foo", want: "secret/data/foo"},
+	}
+
+	// run test
+	for _, test := range tests {
+		got := m.dataPath(test.path)
+		if got != test.want {
+			t.Errorf("dataPath is %s, want %s", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// List is a function to capture the keys for the
+// provided path. Keys ending with a slash represent
+// a folder containing more secrets.
+//
+// The path is transparently rewritten for KV version 2
+// mounts so the keys returned match a KV version 1 mount.
+func (c *Client) List(ctx context.Context, path string) ([]string, error) {
+	// remove any leading and trailing slashes from path
+	p := strings.Trim(path, "/")
+
+	// capture the mount information for the path
+	m := c.mount(ctx, p)
+
+	if m.Version == KVVersion2 {
+		p = m.apiPath(p, "metadata")
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to capture the keys
+	vault, err := c.Vault.Logical().ListWithContext(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list secrets %s: %w", path, err)
+	}
+
+	// return no keys if path does not exist
+	if vault == nil || vault.Data == nil {
+		return nil, nil
+	}
+
+	raw, ok := vault.Data["keys"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to list secrets %s: invalid keys", path)
+	}
+
+	keys := make([]string, 0, len(raw))
+
+	for _, k := range raw {
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("unable to list secrets %s: invalid key %v", path, k)
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Walk is a function to capture the paths of the secrets
+// under the provided path, descending into folders when
+// recursive is set. The paths are returned in sorted order.
+func (c *Client) Walk(ctx context.Context, path string, recursive bool) ([]string, error) {
+	// remove any leading and trailing slashes from path
+	base := strings.Trim(path, "/")
+
+	keys, err := c.List(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+
+	for _, key := range keys {
+		p := base + "/" + key
+
+		// check if the key is a folder
+		if strings.HasSuffix(key, "/") {
+			if !recursive {
+				continue
+			}
+
+			children, err := c.Walk(ctx, p, recursive)
+			if err != nil {
+				return nil, err
+			}
+
+			paths = append(paths, children...)
+
+			continue
+		}
+
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
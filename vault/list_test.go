@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVault_List(t *testing.T) {
+	// setup types
+	mounts := map[string]interface{}{
+		"data": map[string]interface{}{
+			"path":    "secret/",
+			"type":    "kv",
+			"options": map[string]interface{}{"version": "2"},
+		},
+	}
+
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/internal/ui/mounts/secret/app": mounts,
+		"/v1/secret/metadata/app?list=true": map[string]interface{}{
+			"data": map[string]interface{}{"keys": []string{"db/", "api"}},
+		},
+		"/v1/secret/metadata/app/db?list=true": map[string]interface{}{
+			"data": map[string]interface{}{"keys": []string{"replica/", "primary"}},
+		},
+		"/v1/secret/metadata/app/db/replica?list=true": map[string]interface{}{
+			"data": map[string]interface{}{"keys": []string{"east"}},
+		},
+	})
+
+	// setup tests
+	tests := []struct {
+		path      string
+		recursive bool
+		want      []string
+	}{
+		{
+			path: "secret/app",
+			want: []string{"secret/app/api"},
+		},
+		{
+			path:      "/secret/app/",
+			recursive: true,
+			want:      []string{"secret/app/api", "secret/app/db/primary", "secret/app/db/replica/east"},
+		},
+		{
+			path:      "secret/missing",
+			recursive: true,
+			want:      []string{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := vault.Walk(t.Context(), test.path, test.recursive)
+		if err != nil {
+			t.Errorf("Walk for %s returned err: %v", test.path, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Walk for %s is %v, want %v", test.path, got, test.want)
+		}
+	}
+}
+
+func TestVault_List_MountRoot(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/internal/ui/mounts/secret": map[string]interface{}{
+			"data": map[string]interface{}{
+				"path":    "secret/",
+				"type":    "kv",
+				"options": map[string]interface{}{"version": "2"},
+			},
+		},
+		"/v1/secret/metadata?list=true": map[string]interface{}{
+			"data": map[string]interface{}{"keys": []string{"foo", "app/"}},
+		},
+	})
+
+	want := []string{"app/", "foo"}
+
+	// run test
+	got, err := vault.List(t.Context(), "secret/")
+	if err != nil {
+		t.Fatalf("List returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List is %v, want %v", got, want)
+	}
+}
+
+func TestVault_List_KVVersion1(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/secret/app?list=true": map[string]interface{}{
+			"data": map[string]interface{}{"keys": []string{"foo"}},
+		},
+	})
+
+	want := []string{"secret/app/foo"}
+
+	// run test
+	got, err := vault.Walk(t.Context(), "secret/app", true)
+	if err != nil {
+		t.Fatalf("Walk returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk is %v, want %v", got, want)
+	}
+}
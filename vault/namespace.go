@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithNamespace returns a client scoped to the provided
+// Vault Enterprise namespace, sharing the authentication
+// of the client. The client is cached per namespace.
+func (c *Client) WithNamespace(namespace string) *Client {
+	namespace = strings.Trim(namespace, "/")
+
+	// return the client when already scoped to the namespace
+	if namespace == strings.Trim(c.Vault.Namespace(), "/") {
+		return c
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.namespaces[namespace]; ok {
+		return client
+	}
+
+	logrus.Tracef("creating vault client for namespace %s", namespace)
+
+	client := &Client{Vault: c.Vault.WithNamespace(namespace), Accessor: c.Accessor, deadline: c.deadline}
+
+	if c.namespaces == nil {
+		c.namespaces = make(map[string]*Client)
+	}
+
+	c.namespaces[namespace] = client
+
+	return client
+}
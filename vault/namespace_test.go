@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVault_WithNamespace(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{})
+	vault.Vault.SetNamespace("team")
+
+	// run test
+	if got := vault.WithNamespace("/team/"); got != vault {
+		t.Errorf("WithNamespace returned new client for current namespace")
+	}
+
+	got := vault.WithNamespace("other")
+	if got.Vault.Namespace() != "other" {
+		t.Errorf("WithNamespace namespace is %s, want %s", got.Vault.Namespace(), "other")
+	}
+
+	if vault.Vault.Namespace() != "team" {
+		t.Errorf("WithNamespace modified client namespace to %s", vault.Vault.Namespace())
+	}
+
+	if vault.WithNamespace("other") != got {
+		t.Errorf("WithNamespace returned uncached client for namespace")
+	}
+}
+
+func TestVault_New_Namespace(t *testing.T) {
+	// setup mock server
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"superSecretClientToken"}}`))
+	}))
+	defer fake.Close()
+
+	s := &Setup{
+		Addr:       fake.URL,
+		AuthMethod: AppRoleAuthMethod,
+		Namespace:  "team",
+		RoleID:     "myroleid",
+		SecretID:   "superSecretID",
+	}
+
+	// run test
+	got, err := New(t.Context(), s)
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	if got.Vault.Namespace() != "team" {
+		t.Errorf("New namespace is %s, want %s", got.Vault.Namespace(), "team")
+	}
+}
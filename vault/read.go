@@ -3,16 +3,68 @@
 package vault
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/vault/api"
 )
 
+var (
+	// ErrVersionNotSupported defines the error type when a
+	// version is requested for a secret outside a KV version 2 mount.
+	ErrVersionNotSupported = errors.New("secret versions are only supported on kv version 2 mounts")
+
+	// ErrVersionDeleted defines the error type when the
+	// requested version of a secret has been deleted.
+	ErrVersionDeleted = errors.New("secret version has been deleted")
+
+	// ErrVersionDestroyed defines the error type when the
+	// requested version of a secret has been destroyed.
+	ErrVersionDestroyed = errors.New("secret version has been destroyed")
+)
+
 // Read is a function to capture
 // the secret for the provided path.
-func (c *Client) Read(path string) (*api.Secret, error) {
+//
+// The path is transparently rewritten for KV version 2
+// mounts so the data returned matches a KV version 1 mount.
+func (c *Client) Read(ctx context.Context, path string) (*api.Secret, error) {
+	return c.ReadVersion(ctx, path, 0)
+}
+
+// ReadVersion is a function to capture the secret for the
+// provided path at the provided version. A version of 0
+// captures the latest version of the secret.
+func (c *Client) ReadVersion(ctx context.Context, path string, version int) (*api.Secret, error) {
+	// remove any leading slashes from path
+	p := strings.TrimPrefix(path, "/")
+
+	// capture the mount information for the path
+	m := c.mount(ctx, p)
+
+	var params map[string][]string
+
+	switch {
+	case m.Version == KVVersion2:
+		p = m.dataPath(p)
+
+		if version > 0 {
+			params = map[string][]string{
+				"version": {strconv.Itoa(version)},
+			}
+		}
+	case version > 0:
+		return nil, fmt.Errorf("unable to retrieve secret %s version %d: %w", path, version, ErrVersionNotSupported)
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
 	// send API call to capture the secret
-	vault, err := c.Vault.Logical().Read(path)
+	vault, err := c.Vault.Logical().ReadWithDataWithContext(ctx, p, params)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve secret %s: %w", path, err)
 	}
@@ -22,5 +74,37 @@ func (c *Client) Read(path string) (*api.Secret, error) {
 		return nil, fmt.Errorf("unable to retrieve secret %s", path)
 	}
 
+	if m.Version == KVVersion2 {
+		// unwrap the nested data for the KV version 2 secret
+		data, ok := vault.Data["data"].(map[string]interface{})
+		if !ok {
+			return nil, versionError(path, vault.Data["metadata"])
+		}
+
+		vault.Data = data
+	}
+
 	return vault, nil
 }
+
+// versionError is a helper function to create an error
+// reporting the metadata state for a KV version 2 secret
+// that has no data available.
+func versionError(path string, raw interface{}) error {
+	metadata, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unable to retrieve secret %s: no data found", path)
+	}
+
+	version := fmt.Sprint(metadata["version"])
+
+	if destroyed, ok := metadata["destroyed"].(bool); ok && destroyed {
+		return fmt.Errorf("unable to retrieve secret %s version %s: %w", path, version, ErrVersionDestroyed)
+	}
+
+	if deleted, ok := metadata["deletion_time"].(string); ok && len(deleted) > 0 {
+		return fmt.Errorf("unable to retrieve secret %s version %s: %w (deletion_time: %s)", path, version, ErrVersionDeleted, deleted)
+	}
+
+	return fmt.Errorf("unable to retrieve secret %s version %s: no data found", path, version)
+}
@@ -3,8 +3,13 @@
 package vault
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/vault/api"
 )
@@ -30,7 +35,7 @@ func TestVault_Read(t *testing.T) {
 	}
 
 	// run
-	got, err := vault.Read(path)
+	got, err := vault.Read(t.Context(), path)
 	if err != nil {
 		t.Errorf("Read returned err: %v", err)
 	}
@@ -39,3 +44,115 @@ func TestVault_Read(t *testing.T) {
 		t.Errorf("Read is %+v, want %+v", got, want)
 	}
 }
+
+func TestVault_ReadVersion(t *testing.T) {
+	// setup types
+	mounts := map[string]interface{}{
+		"data": map[string]interface{}{
+			"path":    "secret/",
+			"type":    "kv",
+			"options": map[string]interface{}{"version": "2"},
+		},
+	}
+
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/sys/internal/ui/mounts/secret/foo": mounts,
+		"/v1/secret/data/foo?version=1": map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"secret": "old"},
+				"metadata": map[string]interface{}{"version": 1},
+			},
+		},
+		"/v1/secret/data/foo?version=2": testResponse{
+			Status: http.StatusNotFound,
+			Body: map[string]interface{}{
+				"data": map[string]interface{}{
+					"data": nil,
+					"metadata": map[string]interface{}{
+						"version":       2,
+						"deletion_time": "2024-01-01T00:00:00Z",
+						"destroyed":     false,
+					},
+				},
+			},
+		},
+		"/v1/secret/data/foo?version=3": testResponse{
+			Status: http.StatusNotFound,
+			Body: map[string]interface{}{
+				"data": map[string]interface{}{
+					"data": nil,
+					"metadata": map[string]interface{}{
+						"version":       3,
+						"deletion_time": "",
+						"destroyed":     true,
+					},
+				},
+			},
+		},
+	})
+
+	want := map[string]interface{}{
+		"secret": "old",
+	}
+
+	// run test
+	got, err := vault.ReadVersion(t.Context(), "secret/foo", 1)
+	if err != nil {
+		t.Errorf("ReadVersion returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("ReadVersion is %+v, want %+v", got.Data, want)
+	}
+
+	_, err = vault.ReadVersion(t.Context(), "secret/foo", 2)
+	if !errors.Is(err, ErrVersionDeleted) {
+		t.Errorf("ReadVersion returned err %v, want %v", err, ErrVersionDeleted)
+	}
+
+	_, err = vault.ReadVersion(t.Context(), "secret/foo", 3)
+	if !errors.Is(err, ErrVersionDestroyed) {
+		t.Errorf("ReadVersion returned err %v, want %v", err, ErrVersionDestroyed)
+	}
+}
+
+func TestVault_ReadVersion_KVVersion1(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/secret/foo": map[string]interface{}{
+			"data": map[string]interface{}{"secret": "bar"},
+		},
+	})
+
+	// run test
+	_, err := vault.ReadVersion(t.Context(), "secret/foo", 1)
+	if !errors.Is(err, ErrVersionNotSupported) {
+		t.Errorf("ReadVersion returned err %v, want %v", err, ErrVersionNotSupported)
+	}
+}
+
+func TestVault_Read_Canceled(t *testing.T) {
+	// setup types
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// hold the request until the client cancels it
+		<-r.Context().Done()
+	}))
+	t.Cleanup(fake.Close)
+
+	client, err := api.NewClient(&api.Config{Address: fake.URL})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	vault := &Client{Vault: client, mounts: map[string]*mount{"secret/": {Path: "secret/", Version: KVVersion1}}}
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// run test
+	_, err = vault.Read(ctx, "secret/foo")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Read returned err %v, want %v", err, context.Canceled)
+	}
+}
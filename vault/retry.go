@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/vault/api"
+	"github.com/sirupsen/logrus"
+)
+
+// RetryMaxBackoff defines the maximum amount of
+// time to wait between attempts of a request.
+var RetryMaxBackoff = 30 * time.Second
+
+// retry configures the retry policy for the setup on the
+// Vault client configuration, which applies to the login
+// and all requests sent with the client.
+func (s *Setup) retry(conf *api.Config) {
+	// the number of attempts includes the initial request
+	if s.RetryAttempts > 0 {
+		conf.MaxRetries = s.RetryAttempts - 1
+	}
+
+	if s.RetryBackoff > 0 {
+		conf.MinRetryWait = s.RetryBackoff
+		conf.MaxRetryWait = max(s.RetryBackoff, RetryMaxBackoff)
+	}
+
+	// the timeout is applied to each attempt of a request
+	// so the overall deadline is enforced with the context
+	if s.RequestTimeout > 0 {
+		conf.HttpClient.Timeout = s.RequestTimeout
+		conf.Timeout = 0
+	}
+
+	conf.Backoff = s.backoff
+	conf.CheckRetry = checkRetry
+}
+
+// backoff returns the amount of time to wait before the next attempt
+// of a request, doubling the wait for each attempt with the configured
+// jitter applied. Any wait provided by the instance when rate limiting
+// requests (i.e. the Retry-After header) is respected.
+func (s *Setup) backoff(minWait, maxWait time.Duration, attempt int, resp *http.Response) time.Duration {
+	wait := retryablehttp.DefaultBackoff(minWait, maxWait, attempt, resp)
+
+	if s.RetryJitter > 0 {
+		//nolint:gosec // jitter does not require a secure random number
+		wait += time.Duration(rand.Float64() * s.RetryJitter * float64(wait))
+	}
+
+	logrus.Debugf("waiting %s before attempt %d of request", wait, attempt+2)
+
+	return wait
+}
+
+// checkRetry returns whether a request should be attempted again,
+// logging the reason for retrying the request.
+//
+// a write other than a login may have generated credentials (i.e. a
+// dynamic secret) before the instance responded with a server error,
+// so it is only retried when the request never reached the instance
+// (a connection error) or was rejected by rate limiting.
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	retry, err := api.DefaultRetryPolicy(ctx, resp, err)
+	if !retry {
+		return retry, err
+	}
+
+	if resp != nil && resp.Request != nil && !retryableWrite(resp) {
+		logrus.Debugf("not retrying %s request to %s: received status %d", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode)
+
+		return false, err
+	}
+
+	switch {
+	case err != nil:
+		logrus.Debugf("retrying request: %v", err)
+	case resp != nil && resp.Request != nil:
+		logrus.Debugf("retrying %s request to %s: received status %d", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode)
+	case resp != nil:
+		logrus.Debugf("retrying request: received status %d", resp.StatusCode)
+	}
+
+	return retry, err
+}
+
+// retryableWrite returns whether the request for the response can be
+// attempted again, which is always true for reads and logins while a
+// write is only retried when rate limited.
+func retryableWrite(resp *http.Response) bool {
+	switch resp.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+
+	if isLogin(resp.Request.URL.Path) {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests
+}
+
+// isLogin returns whether the path is a login to an auth method
+// (i.e. /v1/auth/approle/login or /v1/auth/ldap/login/octocat).
+func isLogin(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// the auth method can be mounted at a nested path
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "auth" && slices.Contains(parts[i+2:], "login") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// context returns a context derived from the provided context
+// for sending requests with the client that enforces the
+// overall deadline.
+func (c *Client) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, c.deadline)
+}
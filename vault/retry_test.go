@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVault_Retry(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		failures int32
+		requests int32
+		wantErr  bool
+	}{
+		{name: "server error", status: http.StatusServiceUnavailable, attempts: 3, failures: 2, requests: 3},
+		{name: "rate limited", status: http.StatusTooManyRequests, attempts: 3, failures: 2, requests: 3},
+		{name: "attempts exhausted", status: http.StatusBadGateway, attempts: 2, failures: 2, requests: 2, wantErr: true},
+		{name: "not retried", status: http.StatusForbidden, attempts: 3, failures: 1, requests: 1, wantErr: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests atomic.Int32
+
+			fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if requests.Add(1) <= test.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(test.status)
+					_, _ = w.Write([]byte(`{"errors":[]}`))
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"data":{"secret":"bar"}}`))
+			}))
+			t.Cleanup(fake.Close)
+
+			vault, err := New(t.Context(), &Setup{
+				Addr:          fake.URL,
+				AuthMethod:    TokenAuthMethod,
+				Token:         "superSecretToken",
+				RetryAttempts: test.attempts,
+				RetryBackoff:  time.Millisecond,
+				RetryJitter:   0.5,
+			})
+			if err != nil {
+				t.Fatalf("New returned err: %v", err)
+			}
+
+			// skip the mount lookup to only count the read
+			vault.mounts = map[string]*mount{"secret/": {Path: "secret/", Version: KVVersion1}}
+
+			_, err = vault.Read(t.Context(), "secret/foo")
+			if test.wantErr != (err != nil) {
+				t.Errorf("Read returned err: %v, want err %t", err, test.wantErr)
+			}
+
+			if got := requests.Load(); got != test.requests {
+				t.Errorf("Read sent %d requests, want %d", got, test.requests)
+			}
+		})
+	}
+}
+
+func TestVault_Retry_Write(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		status   int
+		requests int32
+		wantErr  bool
+	}{
+		{name: "server error", status: http.StatusInternalServerError, requests: 1, wantErr: true},
+		{name: "unavailable", status: http.StatusServiceUnavailable, requests: 1, wantErr: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, requests: 2},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests atomic.Int32
+
+			fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if requests.Add(1) == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(test.status)
+					_, _ = w.Write([]byte(`{"errors":[]}`))
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"lease_id":"aws/sts/deploy/abcd","data":{"access_key":"AKIA"}}`))
+			}))
+			t.Cleanup(fake.Close)
+
+			vault, err := New(t.Context(), &Setup{
+				Addr:          fake.URL,
+				AuthMethod:    TokenAuthMethod,
+				Token:         "superSecretToken",
+				RetryAttempts: 3,
+				RetryBackoff:  time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("New returned err: %v", err)
+			}
+
+			_, err = vault.Write(t.Context(), "aws/sts/deploy", map[string]interface{}{"ttl": "15m"})
+			if test.wantErr != (err != nil) {
+				t.Errorf("Write returned err: %v, want err %t", err, test.wantErr)
+			}
+
+			// a server error may have generated credentials
+			if got := requests.Load(); got != test.requests {
+				t.Errorf("Write sent %d requests, want %d", got, test.requests)
+			}
+		})
+	}
+}
+
+func TestVault_checkRetry(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "read server error", method: http.MethodGet, path: "/v1/database/creds/readonly", status: http.StatusBadGateway, want: true},
+		{name: "write server error", method: http.MethodPut, path: "/v1/aws/sts/deploy", status: http.StatusBadGateway},
+		{name: "write rate limited", method: http.MethodPut, path: "/v1/aws/sts/deploy", status: http.StatusTooManyRequests, want: true},
+		{name: "login server error", method: http.MethodPut, path: "/v1/auth/approle/login", status: http.StatusBadGateway, want: true},
+		{name: "ldap login server error", method: http.MethodPut, path: "/v1/auth/corp/ldap/login/octocat", status: http.StatusBadGateway, want: true},
+		{name: "connection error", err: errors.New("connection refused"), want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var resp *http.Response
+
+			if test.err == nil {
+				resp = &http.Response{
+					StatusCode: test.status,
+					Request:    httptest.NewRequest(test.method, test.path, nil),
+				}
+			}
+
+			got, _ := checkRetry(t.Context(), resp, test.err)
+			if got != test.want {
+				t.Errorf("checkRetry is %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVault_Retry_Login(t *testing.T) {
+	// setup types
+	var requests atomic.Int32
+
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"superSecretToken"}}`))
+	}))
+	t.Cleanup(fake.Close)
+
+	// run test
+	vault, err := New(t.Context(), &Setup{
+		Addr:          fake.URL,
+		AuthMethod:    LDAPAuthMethod,
+		Username:      "octocat",
+		Password:      "superSecretPassword",
+		RetryAttempts: 2,
+		RetryBackoff:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	if vault.Vault.Token() != "superSecretToken" {
+		t.Errorf("New token is %s, want superSecretToken", vault.Vault.Token())
+	}
+}
+
+func TestVault_Retry_Timeout(t *testing.T) {
+	// setup types
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(fake.Close)
+
+	vault, err := New(t.Context(), &Setup{
+		Addr:           fake.URL,
+		AuthMethod:     TokenAuthMethod,
+		Token:          "superSecretToken",
+		RequestTimeout: 20 * time.Millisecond,
+		RetryAttempts:  100,
+		RetryBackoff:   time.Millisecond,
+		Timeout:        200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	vault.mounts = map[string]*mount{"secret/": {Path: "secret/", Version: KVVersion1}}
+
+	// run test
+	start := time.Now()
+
+	_, err = vault.Read(t.Context(), "secret/foo")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Read returned err %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Read took %s, want less than the overall deadline", elapsed)
+	}
+}
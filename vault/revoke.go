@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+)
+
+// RevokeLease is a function to revoke
+// the lease for the provided ID.
+func (c *Client) RevokeLease(ctx context.Context, id string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to revoke the lease
+	err := c.Vault.Sys().RevokeWithContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to revoke lease %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// RevokeAccessor is a function to revoke the
+// token for the provided token accessor.
+func (c *Client) RevokeAccessor(ctx context.Context, accessor string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to revoke the token
+	err := c.Vault.Auth().Token().RevokeAccessorWithContext(ctx, accessor)
+	if err != nil {
+		return fmt.Errorf("unable to revoke token for accessor %s: %w", accessor, err)
+	}
+
+	return nil
+}
+
+// RevokeSelf is a function to revoke
+// the token set in the client.
+func (c *Client) RevokeSelf(ctx context.Context) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to revoke the token
+	err := c.Vault.Auth().Token().RevokeSelfWithContext(ctx, "")
+	if err != nil {
+		return fmt.Errorf("unable to revoke token: %w", err)
+	}
+
+	return nil
+}
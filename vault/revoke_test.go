@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVault_Revoke(t *testing.T) {
+	// setup mock server
+	revoked := map[string]bool{}
+
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/leases/revoke", "/v1/auth/token/revoke-accessor", "/v1/auth/token/revoke-self":
+			revoked[r.URL.Path] = true
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer fake.Close()
+
+	vault, err := New(t.Context(), &Setup{
+		Addr:       fake.URL,
+		AuthMethod: TokenAuthMethod,
+		Token:      "superSecretToken",
+	})
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	// run test
+	err = vault.RevokeLease(t.Context(), "database/creds/readonly/abcd")
+	if err != nil {
+		t.Errorf("RevokeLease returned err: %v", err)
+	}
+
+	err = vault.RevokeAccessor(t.Context(), "superSecretAccessor")
+	if err != nil {
+		t.Errorf("RevokeAccessor returned err: %v", err)
+	}
+
+	err = vault.RevokeSelf(t.Context())
+	if err != nil {
+		t.Errorf("RevokeSelf returned err: %v", err)
+	}
+
+	if len(revoked) != 3 {
+		t.Errorf("Revoke called %v, want 3 paths", revoked)
+	}
+
+	fake.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	err = vault.RevokeLease(t.Context(), "database/creds/readonly/abcd")
+	if err == nil {
+		t.Errorf("RevokeLease should have returned err")
+	}
+}
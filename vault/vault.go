@@ -3,9 +3,13 @@
 package vault
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/sdk/helper/testcluster/docker"
@@ -13,6 +17,18 @@ import (
 )
 
 const (
+	// AppRoleAuthMethod is used for creating a client capable of AppRole authentication.
+	AppRoleAuthMethod = "approle"
+
+	// CertAuthMethod is used for creating a client capable of TLS certificate authentication.
+	CertAuthMethod = "cert"
+
+	// JWTAuthMethod is used for creating a client capable of JWT/OIDC authentication.
+	JWTAuthMethod = "jwt"
+
+	// KubernetesAuthMethod is used for creating a client capable of Kubernetes authentication.
+	KubernetesAuthMethod = "kubernetes"
+
 	// LDAPAuthMethod is used for creating a client capable of LDAP authentication.
 	LDAPAuthMethod = "ldap"
 
@@ -26,6 +42,16 @@ type (
 	// Vault client docs: https://pkg.go.dev/github.com/hashicorp/vault/api?tab=doc
 	Client struct {
 		Vault *api.Client
+		// accessor of the token created when logging in
+		Accessor string
+
+		// deadline for all requests sent with the client
+		deadline time.Time
+		// cache of secrets engine mounts by path
+		mounts map[string]*mount
+		// cache of clients by namespace
+		namespaces map[string]*Client
+		mu         sync.Mutex
 	}
 
 	// Setup represents the configuration necessary for
@@ -36,12 +62,52 @@ type (
 		Addr string
 		// specifies the authentication method to use
 		AuthMethod string
+		// specifies the mount path for authentication with the auth method
+		AuthMount string
+		// specifies the PEM-encoded CA certificate (or path to it) to verify the vault instances
+		CACert string
+		// specifies the path to a directory of PEM-encoded CA certificates to verify the vault instances
+		CAPath string
+		// specifies the path to the client certificate for communicating with the vault instances
+		ClientCert string
+		// specifies the path to the client key for communicating with the vault instances
+		ClientKey string
+		// specifies whether to skip verification of the vault instances certificate
+		Insecure bool
+		// specifies the token for authentication with JWT auth method
+		JWT string
+		// specifies the file containing the token for authentication with JWT auth method
+		JWTFile string
+		// specifies the file containing the service account token for authentication with Kubernetes auth method
+		KubernetesTokenPath string
+		// specifies the Vault Enterprise namespace for the vault instances
+		Namespace string
 		// specifies the password for authentication with LDAP auth method
 		Password string
+		// specifies the role for authentication with JWT, Kubernetes and cert auth methods
+		Role string
+		// specifies the role ID for authentication with AppRole auth method
+		RoleID string
+		// specifies the secret ID for authentication with AppRole auth method
+		SecretID string
+		// specifies the timeout for each attempt of a request to the vault instances
+		RequestTimeout time.Duration
+		// specifies the maximum number of attempts of a request to the vault instances
+		RetryAttempts int
+		// specifies the initial time to wait between attempts of a request to the vault instances
+		RetryBackoff time.Duration
+		// specifies the fraction of the time to wait between attempts to randomly add
+		RetryJitter float64
+		// specifies the overall deadline for logging in and reading from the vault instances
+		Timeout time.Duration
+		// specifies the server name used for SNI when connecting to the vault instances
+		TLSServerName string
 		// specifies the token for the vault instances
 		Token string
 		// specifies the username for authentication with LDAP auth method
 		Username string
+		// specifies the response wrapping token containing the secret ID for the AppRole auth method
+		WrappedSecretID string
 	}
 )
 
@@ -50,69 +116,175 @@ var (
 	// AuthMethod provided to the client is unsupported.
 	ErrInvalidAuthMethod = errors.New("invalid auth method provided")
 
+	// AppRoleLoginPath defines the path the role information gets
+	// written to for AppRole authentication.
+	AppRoleLoginPath = "/auth/approle/login"
+
+	// CertLoginPath defines the path the certificate information
+	// gets written to for TLS certificate authentication.
+	CertLoginPath = "/auth/cert/login"
+
+	// JWTLoginPath defines the path the token information gets
+	// written to for JWT authentication.
+	JWTLoginPath = "/auth/jwt/login"
+
+	// KubernetesLoginPath defines the path the service account
+	// token gets written to for Kubernetes authentication.
+	KubernetesLoginPath = "/auth/kubernetes/login"
+
+	// KubernetesTokenPath defines the default path to the
+	// projected service account token within a pod.
+	//
+	//nolint: gosec // false pos
+	KubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
 	// LDAPUserPath defines the path the user information gets
 	// written to after success LDAP authentication.
 	LDAPUserPath = "/auth/ldap/login/%s"
+
+	// MountLoginPath defines the path the login information gets
+	// written to for an auth method enabled at a custom mount path.
+	MountLoginPath = "/auth/%s/login"
+
+	// MountUserPath defines the path the user information gets written
+	// to for the LDAP auth method enabled at a custom mount path.
+	MountUserPath = "/auth/%s/login/%s"
+
+	// authMethods defines the list of supported auth methods.
+	authMethods = []string{
+		AppRoleAuthMethod,
+		CertAuthMethod,
+		JWTAuthMethod,
+		KubernetesAuthMethod,
+		LDAPAuthMethod,
+		TokenAuthMethod,
+	}
 )
 
 // New returns a Secret implementation that integrates with a Vault secrets engine.
-func New(s *Setup) (*Client, error) {
-	conf := &api.Config{Address: s.Addr}
+func New(ctx context.Context, s *Setup) (*Client, error) {
+	// capture the auth method specific login for the vault client
+	var login func(context.Context, *api.Client) (*api.Secret, error)
 
-	// add auth method specific vault client configuration
 	switch s.AuthMethod {
+	case AppRoleAuthMethod:
+		login = s.loginAppRole
+	case CertAuthMethod:
+		login = s.loginCert
+	case JWTAuthMethod:
+		login = s.loginJWT
+	case KubernetesAuthMethod:
+		login = s.loginKubernetes
 	case LDAPAuthMethod:
-		logrus.Tracef("creating vault client with %s auth method", LDAPAuthMethod)
-		// create Vault client
-		vault, err := api.NewClient(conf)
-		if err != nil {
-			return nil, err
-		}
-
-		// options for passing the password
-		options := map[string]interface{}{
-			"password": s.Password,
-		}
-
-		// the login path
-		path := fmt.Sprintf(LDAPUserPath, s.Username)
-
-		// call to get a user token
-		user, err := vault.Logical().Write(path, options)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get user token: %w", err)
-		}
-
-		// vault will return a nil Auth struct with no error if path is correct but password fails
-		if user == nil || user.Auth == nil {
-			return nil, fmt.Errorf("unable to set user token: authentication failed")
-		}
+		login = s.loginLDAP
+	case TokenAuthMethod:
+		// no login necessary with token auth method
+	default:
+		return nil, fmt.Errorf("%w: %s (Valid auth methods: %s)",
+			ErrInvalidAuthMethod,
+			s.AuthMethod,
+			strings.Join(authMethods, ", "),
+		)
+	}
 
-		// set Vault API token in client
-		vault.SetToken(user.Auth.ClientToken)
+	logrus.Tracef("creating vault client with %s auth method", s.AuthMethod)
 
-		return &Client{Vault: vault}, nil
-	case TokenAuthMethod:
-		logrus.Tracef("creating vault client with %s auth method", TokenAuthMethod)
+	conf, err := s.config()
+	if err != nil {
+		return nil, err
+	}
+
+	// create Vault client
+	vault, err := api.NewClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// set Vault Enterprise namespace in client
+	if len(s.Namespace) > 0 {
+		vault.SetNamespace(s.Namespace)
+	}
+
+	c := &Client{Vault: vault}
 
-		// create Vault client
-		vault, err := api.NewClient(conf)
-		if err != nil {
-			return nil, err
-		}
+	// enforce the overall deadline for all requests
+	if s.Timeout > 0 {
+		c.deadline = time.Now().Add(s.Timeout)
+	}
 
+	if login == nil {
 		// set Vault API token in client
 		vault.SetToken(s.Token)
 
-		return &Client{Vault: vault}, nil
-	default:
-		return nil, fmt.Errorf("%w: %s (Valid auth methods: %s, %s)",
-			ErrInvalidAuthMethod,
-			s.AuthMethod,
-			LDAPAuthMethod,
-			TokenAuthMethod,
-		)
+		return c, nil
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// call to get a user token
+	user, err := login(ctx, vault)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get user token: %w", err)
+	}
+
+	// vault will return a nil Auth struct with no error if path is correct but password fails
+	if user == nil || user.Auth == nil {
+		return nil, fmt.Errorf("unable to set user token: authentication failed")
 	}
+
+	// set Vault API token in client
+	vault.SetToken(user.Auth.ClientToken)
+
+	c.Accessor = user.Auth.Accessor
+
+	return c, nil
+}
+
+// config returns the Vault client configuration for the setup.
+func (s *Setup) config() (*api.Config, error) {
+	conf := api.DefaultConfig()
+	if conf.Error != nil {
+		return nil, fmt.Errorf("unable to create vault client configuration: %w", conf.Error)
+	}
+
+	conf.Address = s.Addr
+
+	// configure the retry policy for requests
+	s.retry(conf)
+
+	// only override the TLS configuration when TLS options are provided
+	if len(s.CACert) == 0 && len(s.CAPath) == 0 &&
+		len(s.ClientCert) == 0 && len(s.ClientKey) == 0 &&
+		len(s.TLSServerName) == 0 && !s.Insecure {
+		return conf, nil
+	}
+
+	if s.Insecure {
+		logrus.Warn("skipping verification of vault instance certificate")
+	}
+
+	tls := &api.TLSConfig{
+		CAPath:        s.CAPath,
+		ClientCert:    s.ClientCert,
+		ClientKey:     s.ClientKey,
+		TLSServerName: s.TLSServerName,
+		Insecure:      s.Insecure,
+	}
+
+	// the CA certificate can be provided as PEM-encoded contents or a path
+	if strings.HasPrefix(strings.TrimSpace(s.CACert), "-----BEGIN") {
+		tls.CACertBytes = []byte(s.CACert)
+	} else {
+		tls.CACert = s.CACert
+	}
+
+	err := conf.ConfigureTLS(tls)
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure tls for vault client: %w", err)
+	}
+
+	return conf, nil
 }
 
 // NewMock returns a test unsealed Vault
@@ -3,11 +3,14 @@
 package vault
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/hashicorp/vault/api"
 )
 
 func TestVault_New(t *testing.T) {
@@ -46,7 +49,7 @@ func TestVault_New(t *testing.T) {
 
 	// run test
 	for _, test := range tests {
-		vault, err := New(test.setup)
+		vault, err := New(t.Context(), test.setup)
 		if !errors.Is(err, test.err) {
 			t.Errorf("New returned err: %v", err)
 		}
@@ -73,29 +76,83 @@ func TestVault_New_Error(t *testing.T) {
 				AuthMethod: "fake",
 				Token:      "",
 			},
-			err: fmt.Errorf("invalid auth method provided: fake (Valid auth methods: ldap, token)"),
+			err: fmt.Errorf("invalid auth method provided: fake (Valid auth methods: approle, cert, jwt, kubernetes, ldap, token)"),
 		},
 		{ // failure with no address
 			setup: &Setup{
 				AuthMethod: "fake",
 				Token:      "",
 			},
-			err: fmt.Errorf("invalid auth method provided: fake (Valid auth methods: ldap, token)"),
+			err: fmt.Errorf("invalid auth method provided: fake (Valid auth methods: approle, cert, jwt, kubernetes, ldap, token)"),
 		},
 		{ // failure with no auth method
 			setup: &Setup{
 				Addr:  "!@#$%^&*()",
 				Token: "",
 			},
-			err: fmt.Errorf("invalid auth method provided: fake (Valid auth methods: ldap, token)"),
+			err: fmt.Errorf("invalid auth method provided: fake (Valid auth methods: approle, cert, jwt, kubernetes, ldap, token)"),
 		},
 	}
 
 	// run test
 	for _, test := range tests {
-		_, err := New(test.setup)
+		_, err := New(t.Context(), test.setup)
 		if errors.Is(err, test.err) {
 			t.Errorf("New returned err: %v", err)
 		}
 	}
 }
+
+// testResponse represents a response from the fake
+// Vault server with a status code other than 200.
+type testResponse struct {
+	Status int
+	Body   interface{}
+}
+
+// newTestServer returns the address of a fake Vault server that
+// responds with the provided data by path (including any query).
+func newTestServer(t *testing.T, responses map[string]interface{}) string {
+	t.Helper()
+
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if len(r.URL.RawQuery) > 0 {
+			key += "?" + r.URL.RawQuery
+		}
+
+		body, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if resp, ok := body.(testResponse); ok {
+			w.WriteHeader(resp.Status)
+
+			body = resp.Body
+		}
+
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(fake.Close)
+
+	return fake.URL
+}
+
+// newTestClient returns a client backed by a fake Vault server that
+// responds with the provided data by path (including any query).
+func newTestClient(t *testing.T, responses map[string]interface{}) *Client {
+	t.Helper()
+
+	client, err := api.NewClient(&api.Config{Address: newTestServer(t, responses)})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	return &Client{Vault: client}
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// Write is a function to capture the secret generated
+// for the provided path with the provided data, used
+// by dynamic secrets engines that require a POST request.
+func (c *Client) Write(ctx context.Context, path string, data map[string]interface{}) (*api.Secret, error) {
+	// remove any leading slashes from path
+	p := strings.TrimPrefix(path, "/")
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	// send API call to generate the secret
+	vault, err := c.Vault.Logical().WriteWithContext(ctx, p, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate secret %s: %w", path, err)
+	}
+
+	// return nil if secret was not generated
+	if vault == nil {
+		return nil, fmt.Errorf("unable to generate secret %s", path)
+	}
+
+	return vault, nil
+}
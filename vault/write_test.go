@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVault_Write(t *testing.T) {
+	// setup types
+	vault := newTestClient(t, map[string]interface{}{
+		"/v1/aws/sts/deploy": map[string]interface{}{
+			"lease_id":       "aws/sts/deploy/abcd",
+			"lease_duration": 900,
+			"data": map[string]interface{}{
+				"access_key": "AKIA",
+				"secret_key": "superSecretKey",
+			},
+		},
+	})
+
+	want := map[string]interface{}{
+		"access_key": "AKIA",
+		"secret_key": "superSecretKey",
+	}
+
+	// run test
+	got, err := vault.Write(t.Context(), "/aws/sts/deploy", map[string]interface{}{"ttl": "15m"})
+	if err != nil {
+		t.Fatalf("Write returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("Write is %+v, want %+v", got.Data, want)
+	}
+
+	if got.LeaseID != "aws/sts/deploy/abcd" {
+		t.Errorf("Write lease is %s, want %s", got.LeaseID, "aws/sts/deploy/abcd")
+	}
+
+	_, err = vault.Write(t.Context(), "aws/sts/missing", nil)
+	if err == nil {
+		t.Errorf("Write should have returned err")
+	}
+}